@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	absenceWindowTitle  = "Absences"
+	absenceWindowWidth  = 700
+	absenceWindowHeight = 560
+)
+
+// AbsenceWindow records vacation, sick and other absences picked from a calendar
+type AbsenceWindow struct {
+	window        fyne.Window
+	storage       *Storage
+	selected      time.Time
+	selectedLabel *widget.Label
+	untilEntry    *widget.Entry
+	typeSelect    *widget.Select
+	halfCheck     *widget.Check
+	noteEntry     *widget.Entry
+	list          *widget.List
+	vacationLabel *widget.Label
+	absences      []Absence
+}
+
+func NewAbsenceWindow(app fyne.App, storage *Storage) *AbsenceWindow {
+	now := time.Now()
+	aw := &AbsenceWindow{
+		window:        app.NewWindow(absenceWindowTitle),
+		storage:       storage,
+		selected:      time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local),
+		selectedLabel: widget.NewLabel(""),
+		untilEntry:    widget.NewEntry(),
+		typeSelect:    widget.NewSelect(absenceTypes, nil),
+		halfCheck:     widget.NewCheck("Half day", nil),
+		noteEntry:     widget.NewEntry(),
+		vacationLabel: widget.NewLabel(""),
+	}
+	aw.untilEntry.SetPlaceHolder("YYYY-MM-DD (optional)")
+	aw.typeSelect.SetSelected(absenceVacation)
+
+	calendar := widget.NewCalendar(now, func(date time.Time) {
+		aw.selected = date
+		aw.refresh()
+	})
+	aw.list = widget.NewList(
+		func() int { return len(aw.absences) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			absence := aw.absences[id]
+			item.(*widget.Label).SetText(fmt.Sprintf("%s  %s  %s", absence.Date, absence.Label(), absence.Note))
+		},
+	)
+
+	form := widget.NewForm(
+		widget.NewFormItem("From", aw.selectedLabel),
+		widget.NewFormItem("Until", aw.untilEntry),
+		widget.NewFormItem("Type", aw.typeSelect),
+		widget.NewFormItem("", aw.halfCheck),
+		widget.NewFormItem("Note", aw.noteEntry),
+	)
+	buttons := container.NewHBox(
+		widget.NewButton("Add", aw.add),
+		widget.NewButton("Remove", aw.remove),
+	)
+
+	aw.window.SetContent(container.NewHSplit(
+		container.NewVScroll(container.NewVBox(calendar, form, buttons)),
+		container.NewBorder(nil, aw.vacationLabel, nil, nil, aw.list),
+	))
+	aw.window.Resize(fyne.NewSize(absenceWindowWidth, absenceWindowHeight))
+	aw.refresh()
+
+	return aw
+}
+
+// dateRange returns the selected day and the optional end date
+func (aw *AbsenceWindow) dateRange() (time.Time, time.Time, error) {
+	if aw.untilEntry.Text == "" {
+		return aw.selected, aw.selected, nil
+	}
+	return parseAbsenceDates([]string{aw.selected.Format("2006-01-02"), aw.untilEntry.Text})
+}
+
+func (aw *AbsenceWindow) add() {
+	from, to, err := aw.dateRange()
+	if err != nil {
+		dialog.ShowError(err, aw.window)
+		return
+	}
+	if _, err := aw.storage.AddAbsence(from, to, aw.typeSelect.Selected, aw.halfCheck.Checked, aw.noteEntry.Text); err != nil {
+		dialog.ShowError(err, aw.window)
+		return
+	}
+	aw.untilEntry.SetText("")
+	aw.noteEntry.SetText("")
+	aw.refresh()
+}
+
+func (aw *AbsenceWindow) remove() {
+	from, to, err := aw.dateRange()
+	if err != nil {
+		dialog.ShowError(err, aw.window)
+		return
+	}
+	if _, err := aw.storage.RemoveAbsences(from, to); err != nil {
+		dialog.ShowError(err, aw.window)
+		return
+	}
+	aw.untilEntry.SetText("")
+	aw.refresh()
+}
+
+// refresh shows the selected day and the absences and vacation balance of its year
+func (aw *AbsenceWindow) refresh() {
+	aw.selectedLabel.SetText(aw.selected.Format("Mon 2006-01-02"))
+
+	absences, err := aw.storage.LoadAbsences()
+	if err != nil {
+		aw.vacationLabel.SetText("Error: " + err.Error())
+		return
+	}
+	aw.absences = absences.inYear(aw.selected.Year())
+	aw.list.Refresh()
+
+	vacation, err := aw.storage.VacationBalance(aw.selected.Year())
+	if err != nil {
+		aw.vacationLabel.SetText("Error: " + err.Error())
+		return
+	}
+	aw.vacationLabel.SetText(formatVacationBalance(vacation))
+}
+
+func (aw *AbsenceWindow) Show() {
+	aw.window.Show()
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Absence types
+const (
+	absenceVacation = "vacation"
+	absenceSick     = "sick"
+	absenceTraining = "training"
+	absenceUnpaid   = "unpaid"
+	// absenceLieu is a day off taken to reduce overtime; its credit is booked against the flextime balance
+	absenceLieu = "lieu"
+)
+
+var absenceTypes = []string{absenceVacation, absenceSick, absenceTraining, absenceUnpaid, absenceLieu}
+
+var absencesCSVHeader = []string{"date", "type", "fraction", "note"}
+
+// Absence is a full or half day away from work
+type Absence struct {
+	Date string
+	Type string
+	// Fraction is the part of the day taken: 1 for a full day, 0.5 for a half day
+	Fraction float64
+	Note     string
+}
+
+// Label returns the type for display, marking half days
+func (a Absence) Label() string {
+	if a.Fraction < 1 {
+		return a.Type + " (half day)"
+	}
+	return a.Type
+}
+
+// Absences maps dates (YYYY-MM-DD) to the absence on that day
+type Absences map[string]Absence
+
+// Target returns the day's target reduced by the credit of an absence on that day
+func (a Absences) Target(config *Config, date time.Time) time.Duration {
+	target := config.DailyTarget(date)
+	absence, ok := a[date.Format("2006-01-02")]
+	if !ok {
+		return target
+	}
+	credit := time.Duration(float64(target) * absence.Fraction * config.AbsenceCreditFor(absence.Type))
+	return (target - credit).Round(time.Second)
+}
+
+// parseAbsenceType checks that value names an absence type
+func parseAbsenceType(value string) (string, error) {
+	value = strings.ToLower(value)
+	for _, absenceType := range absenceTypes {
+		if value == absenceType {
+			return value, nil
+		}
+	}
+	return "", fmt.Errorf("unknown absence type %q, use one of %s", value, strings.Join(absenceTypes, ", "))
+}
+
+// LoadAbsences returns the stored absences by date
+func (s *Storage) LoadAbsences() (Absences, error) {
+	absences := make(Absences)
+	if s.absencesFile == "" {
+		return absences, nil
+	}
+	file, err := os.Open(s.absencesFile)
+	if os.IsNotExist(err) {
+		return absences, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	if _, err := reader.Read(); err != nil {
+		if err == io.EOF {
+			return absences, nil
+		}
+		return nil, err
+	}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return absences, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) < 3 {
+			continue // Skip invalid records
+		}
+		fraction, err := strconv.ParseFloat(record[2], 64)
+		if err != nil {
+			continue
+		}
+		absence := Absence{Date: record[0], Type: record[1], Fraction: fraction}
+		if len(record) >= 4 {
+			absence.Note = record[3]
+		}
+		absences[absence.Date] = absence
+	}
+}
+
+// saveAbsences rewrites the absences file, sorted by date
+func (s *Storage) saveAbsences(absences Absences) error {
+	dates := make([]string, 0, len(absences))
+	for date := range absences {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.Write(absencesCSVHeader)
+	for _, date := range dates {
+		absence := absences[date]
+		writer.Write([]string{date, absence.Type, strconv.FormatFloat(absence.Fraction, 'f', -1, 64), absence.Note})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return err
+	}
+	return os.WriteFile(s.absencesFile, buf.Bytes(), 0644)
+}
+
+// AddAbsence records an absence on every day with a target from from to to, replacing
+// earlier entries on those days. It returns the number of days recorded.
+func (s *Storage) AddAbsence(from, to time.Time, absenceType string, half bool, note string) (int, error) {
+	config, err := s.LoadConfig()
+	if err != nil {
+		return 0, err
+	}
+	absences, err := s.LoadAbsences()
+	if err != nil {
+		return 0, err
+	}
+
+	fraction := 1.0
+	if half {
+		fraction = 0.5
+	}
+	added := 0
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if config.DailyTarget(day) == 0 {
+			continue // Weekends and holidays need no absence
+		}
+		date := day.Format("2006-01-02")
+		absences[date] = Absence{Date: date, Type: absenceType, Fraction: fraction, Note: note}
+		added++
+	}
+	if added == 0 {
+		return 0, nil
+	}
+	return added, s.saveAbsences(absences)
+}
+
+// RemoveAbsences deletes the absences from from to to and returns how many were removed
+func (s *Storage) RemoveAbsences(from, to time.Time) (int, error) {
+	absences, err := s.LoadAbsences()
+	if err != nil {
+		return 0, err
+	}
+	first, last := from.Format("2006-01-02"), to.Format("2006-01-02")
+	removed := 0
+	for date := range absences {
+		if date >= first && date <= last {
+			delete(absences, date)
+			removed++
+		}
+	}
+	if removed == 0 {
+		return 0, nil
+	}
+	return removed, s.saveAbsences(absences)
+}
+
+// VacationBalance is the vacation allowance of a year and the days taken
+type VacationBalance struct {
+	Year      int
+	Allowance float64
+	Taken     float64
+}
+
+// Remaining returns the vacation days left in the year
+func (v VacationBalance) Remaining() float64 {
+	return v.Allowance - v.Taken
+}
+
+// VacationBalance sums the vacation days taken or planned in the given year
+func (s *Storage) VacationBalance(year int) (VacationBalance, error) {
+	config, err := s.LoadConfig()
+	if err != nil {
+		return VacationBalance{}, err
+	}
+	absences, err := s.LoadAbsences()
+	if err != nil {
+		return VacationBalance{}, err
+	}
+
+	balance := VacationBalance{Year: year, Allowance: config.VacationDays}
+	prefix := fmt.Sprintf("%04d-", year)
+	for date, absence := range absences {
+		if absence.Type == absenceVacation && strings.HasPrefix(date, prefix) {
+			balance.Taken += absence.Fraction
+		}
+	}
+	return balance, nil
+}
+
+// inYear returns the absences of a year sorted by date
+func (a Absences) inYear(year int) []Absence {
+	prefix := fmt.Sprintf("%04d-", year)
+	var result []Absence
+	for date, absence := range a {
+		if strings.HasPrefix(date, prefix) {
+			result = append(result, absence)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Date < result[j].Date })
+	return result
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAbsencesCreditTargets(t *testing.T) {
+	storage := newTestStorage(t)
+	config := &Config{WeeklyTargetHours: 40, FlextimeStart: "2025-03-10", VacationDays: 30, AbsenceCredit: map[string]float64{"unpaid": 0}}
+	if err := storage.SaveConfig(config); err != nil {
+		t.Fatal(err)
+	}
+	monday := time.Date(2025, 3, 10, 0, 0, 0, 0, time.Local)
+
+	// Monday to Sunday vacation only records the five working days
+	added, err := storage.AddAbsence(monday, monday.AddDate(0, 0, 6), absenceVacation, false, "")
+	if err != nil || added != 5 {
+		t.Fatalf("Expected 5 vacation days, got %d (%v)", added, err)
+	}
+	// Replace Wednesday with a half sick day and Friday with unpaid leave
+	if _, err := storage.AddAbsence(monday.AddDate(0, 0, 2), monday.AddDate(0, 0, 2), absenceSick, true, "doctor"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := storage.AddAbsence(monday.AddDate(0, 0, 4), monday.AddDate(0, 0, 4), absenceUnpaid, false, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := storage.Flextime(monday.AddDate(0, 0, 6))
+	if err != nil {
+		t.Fatalf("Flextime failed: %v", err)
+	}
+	// Half of Wednesday and the unpaid Friday remain as target
+	if len(report.Weeks) != 1 || report.Weeks[0].Target != 12*time.Hour {
+		t.Errorf("Expected a remaining target of 12h, got %+v", report.Weeks)
+	}
+
+	vacation, err := storage.VacationBalance(2025)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vacation.Taken != 3 || vacation.Remaining() != 27 {
+		t.Errorf("Expected 3 vacation days taken and 27 remaining, got %+v", vacation)
+	}
+
+	days, err := storage.DailySummaries(monday.AddDate(0, 0, 2), monday.AddDate(0, 0, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if days[0].Note() != "sick (half day)" || days[0].Target != 4*time.Hour {
+		t.Errorf("Unexpected Wednesday: %q with target %v", days[0].Note(), days[0].Target)
+	}
+
+	removed, err := storage.RemoveAbsences(monday, monday.AddDate(0, 0, 1))
+	if err != nil || removed != 2 {
+		t.Errorf("Expected 2 removed absences, got %d (%v)", removed, err)
+	}
+	absences, _ := storage.LoadAbsences()
+	if len(absences) != 3 || absences["2025-03-12"].Note != "doctor" {
+		t.Errorf("Unexpected remaining absences: %v", absences)
+	}
+}
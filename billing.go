@@ -0,0 +1,399 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+const defaultCurrency = "EUR"
+
+// BillingConfig holds the hourly rates and invoice settings for freelance work
+type BillingConfig struct {
+	// Currency is the ISO 4217 code used unless a client has its own, "EUR" by default
+	Currency   string  `json:"currency,omitempty"`
+	VATPercent float64 `json:"vat_percent"`
+	// InvoicePrefix is put in front of the invoice numbers, which count up per year ("2025-0001")
+	InvoicePrefix string `json:"invoice_prefix,omitempty"`
+	// Issuer is the name and address printed as sender, lines separated by "\n"
+	Issuer string `json:"issuer,omitempty"`
+	// Rounding adjusts the billed time; the recorded sessions are never changed
+	Rounding *RoundingPolicy          `json:"rounding,omitempty"`
+	Clients  map[string]BillingClient `json:"clients"`
+}
+
+// BillingClient is a customer with the projects billed to it
+type BillingClient struct {
+	Name    string `json:"name"`
+	Address string `json:"address,omitempty"`
+	// Rate is the hourly rate of projects without their own rate
+	Rate float64 `json:"rate"`
+	// Currency, VATPercent and Rounding override the billing defaults, e.g. 0% VAT for reverse charge
+	Currency   string          `json:"currency,omitempty"`
+	VATPercent *float64        `json:"vat_percent,omitempty"`
+	Rounding   *RoundingPolicy `json:"rounding,omitempty"`
+	// Projects maps the project names of sessions to their hourly rate; 0 uses Rate
+	Projects map[string]float64 `json:"projects"`
+}
+
+// currency returns the client's currency or the default one
+func (b *BillingConfig) currency(client BillingClient) string {
+	if client.Currency != "" {
+		return strings.ToUpper(client.Currency)
+	}
+	if b.Currency != "" {
+		return strings.ToUpper(b.Currency)
+	}
+	return defaultCurrency
+}
+
+// vatPercent returns the client's VAT rate or the default one
+func (b *BillingConfig) vatPercent(client BillingClient) float64 {
+	if client.VATPercent != nil {
+		return *client.VATPercent
+	}
+	return b.VATPercent
+}
+
+// rounding returns the client's rounding policy or the default one, nil if time is billed exactly
+func (b *BillingConfig) rounding(client BillingClient) *RoundingPolicy {
+	if client.Rounding != nil {
+		return client.Rounding
+	}
+	return b.Rounding
+}
+
+// rate returns the hourly rate of a project of the client
+func (client BillingClient) rate(project string) float64 {
+	if rate := client.Projects[project]; rate > 0 {
+		return rate
+	}
+	return client.Rate
+}
+
+// clientNames returns the client IDs sorted for selection lists
+func (b *BillingConfig) clientNames() []string {
+	if b == nil {
+		return nil
+	}
+	names := make([]string, 0, len(b.Clients))
+	for id := range b.Clients {
+		names = append(names, id)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validateBilling checks the "billing" setting
+func validateBilling(b *BillingConfig) error {
+	if b == nil {
+		return nil
+	}
+	validCurrency := func(code string) bool {
+		if len(code) != 3 {
+			return false
+		}
+		for _, r := range strings.ToUpper(code) {
+			if r < 'A' || r > 'Z' {
+				return false
+			}
+		}
+		return true
+	}
+	if b.Currency != "" && !validCurrency(b.Currency) {
+		return fmt.Errorf("billing: currency must be a three-letter code such as \"EUR\", got %q", b.Currency)
+	}
+	if b.VATPercent < 0 || b.VATPercent > 100 {
+		return fmt.Errorf("billing: vat_percent must be between 0 and 100")
+	}
+	if err := b.Rounding.validate(); err != nil {
+		return fmt.Errorf("billing: rounding: %w", err)
+	}
+
+	owners := make(map[string]string)
+	for _, id := range b.clientNames() {
+		client := b.Clients[id]
+		if client.Currency != "" && !validCurrency(client.Currency) {
+			return fmt.Errorf("billing: client %s: invalid currency %q", id, client.Currency)
+		}
+		if vat := client.VATPercent; vat != nil && (*vat < 0 || *vat > 100) {
+			return fmt.Errorf("billing: client %s: vat_percent must be between 0 and 100", id)
+		}
+		if err := client.Rounding.validate(); err != nil {
+			return fmt.Errorf("billing: client %s: rounding: %w", id, err)
+		}
+		if client.Rate < 0 {
+			return fmt.Errorf("billing: client %s: rate must not be negative", id)
+		}
+		if len(client.Projects) == 0 {
+			return fmt.Errorf("billing: client %s has no projects", id)
+		}
+		for project, rate := range client.Projects {
+			if owner, ok := owners[project]; ok {
+				return fmt.Errorf("billing: project %q belongs to clients %s and %s", project, owner, id)
+			}
+			owners[project] = id
+			if rate < 0 || client.rate(project) == 0 {
+				return fmt.Errorf("billing: client %s: project %q needs a positive rate", id, project)
+			}
+		}
+	}
+	return nil
+}
+
+// InvoiceLine is the billed time of one project; Hours is rounded, Recorded is the exact time
+type InvoiceLine struct {
+	Project  string
+	Sessions int
+	Hours    time.Duration
+	Recorded time.Duration
+	Rate     float64
+	Amount   int64 // in cents
+}
+
+// Invoice is an invoice for the sessions of one client in a period; amounts are in cents
+type Invoice struct {
+	Number     string
+	Date       time.Time
+	ClientID   string
+	Client     BillingClient
+	Issuer     string
+	From, To   time.Time
+	Currency   string
+	VATPercent float64
+	Rounding   *RoundingPolicy
+	Lines      []InvoiceLine
+	Net        int64
+	VAT        int64
+	Total      int64
+	// SessionKeys identifies the billed sessions, see sessionKey
+	SessionKeys []string
+}
+
+// InvoiceRecord is the stored summary of an issued invoice with the sessions it bills
+type InvoiceRecord struct {
+	Number   string   `json:"number"`
+	Date     string   `json:"date"`
+	Client   string   `json:"client"`
+	From     string   `json:"from"`
+	To       string   `json:"to"`
+	Currency string   `json:"currency"`
+	Total    int64    `json:"total_cents"`
+	Sessions []string `json:"sessions"`
+}
+
+// LoadInvoices returns the issued invoices in the order they were recorded
+func (s *Storage) LoadInvoices() ([]InvoiceRecord, error) {
+	if s.invoicesFile == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(s.invoicesFile)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var records []InvoiceRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", s.invoicesFile, err)
+	}
+	return records, nil
+}
+
+// invoicedSessions returns the keys of all sessions billed so far
+func invoicedSessions(records []InvoiceRecord) map[string]string {
+	invoiced := make(map[string]string)
+	for _, record := range records {
+		for _, key := range record.Sessions {
+			invoiced[key] = record.Number
+		}
+	}
+	return invoiced
+}
+
+// nextInvoiceNumber returns the number following the invoices of the same year, e.g. "INV-2025-0003"
+func nextInvoiceNumber(prefix string, year int, records []InvoiceRecord) string {
+	yearPrefix := fmt.Sprintf("%s%d-", prefix, year)
+	last := 0
+	for _, record := range records {
+		var seq int
+		if rest, ok := strings.CutPrefix(record.Number, yearPrefix); ok {
+			if _, err := fmt.Sscanf(rest, "%d", &seq); err == nil && seq > last {
+				last = seq
+			}
+		}
+	}
+	return fmt.Sprintf("%s%04d", yearPrefix, last+1)
+}
+
+// PrepareInvoice bills the sessions of the client's projects from from to to (today if zero)
+// that haven't been invoiced yet. Nothing is stored until RecordInvoice is called.
+func (s *Storage) PrepareInvoice(clientID string, from, to time.Time) (*Invoice, error) {
+	config, err := s.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	billing := config.Billing
+	if billing == nil {
+		return nil, fmt.Errorf("no billing settings in %s", s.configFile)
+	}
+	client, ok := billing.Clients[clientID]
+	if !ok {
+		return nil, fmt.Errorf("unknown client %q, use one of %s", clientID, strings.Join(billing.clientNames(), ", "))
+	}
+	records, err := s.LoadInvoices()
+	if err != nil {
+		return nil, err
+	}
+	today, err := s.Today()
+	if err != nil {
+		return nil, err
+	}
+	if to.IsZero() {
+		to = today
+	}
+	sessions, err := s.LoadSessions(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice := &Invoice{
+		Number:     nextInvoiceNumber(billing.InvoicePrefix, today.Year(), records),
+		Date:       today,
+		ClientID:   clientID,
+		Client:     client,
+		Issuer:     billing.Issuer,
+		From:       from,
+		To:         to,
+		Currency:   billing.currency(client),
+		VATPercent: billing.vatPercent(client),
+		Rounding:   billing.rounding(client),
+	}
+	invoiced := invoicedSessions(records)
+	lines := make(map[string]*InvoiceLine)
+	dayWork := make(map[[2]string]time.Duration) // Work per project and day for rounding per day
+	for _, session := range sessions {
+		work := session.Duration - session.BreakTime
+		if _, ok := client.Projects[session.Project]; !ok || work <= 0 {
+			continue
+		}
+		key := sessionKey(session)
+		if _, ok := invoiced[key]; ok {
+			continue
+		}
+		line, ok := lines[session.Project]
+		if !ok {
+			line = &InvoiceLine{Project: session.Project, Rate: client.rate(session.Project)}
+			lines[session.Project] = line
+		}
+		line.Sessions++
+		line.Recorded += time.Duration(work) * time.Second
+		if invoice.Rounding.perDay() {
+			dayWork[[2]string{session.Project, session.Date}] += time.Duration(work) * time.Second
+		} else {
+			line.Hours += invoice.Rounding.apply(time.Duration(work) * time.Second)
+		}
+		invoice.SessionKeys = append(invoice.SessionKeys, key)
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("no sessions to bill for %s in this period", clientID)
+	}
+	for key, work := range dayWork {
+		lines[key[0]].Hours += invoice.Rounding.apply(work)
+	}
+
+	for _, line := range lines {
+		line.Amount = int64(math.Round(line.Hours.Hours() * line.Rate * 100))
+		invoice.Lines = append(invoice.Lines, *line)
+		invoice.Net += line.Amount
+	}
+	sort.Slice(invoice.Lines, func(i, j int) bool { return invoice.Lines[i].Project < invoice.Lines[j].Project })
+	invoice.VAT = int64(math.Round(float64(invoice.Net) * invoice.VATPercent / 100))
+	invoice.Total = invoice.Net + invoice.VAT
+	return invoice, nil
+}
+
+// BilledHours returns the billed time of all lines and the exact time recorded for them
+func (invoice *Invoice) BilledHours() (billed, recorded time.Duration) {
+	for _, line := range invoice.Lines {
+		billed += line.Hours
+		recorded += line.Recorded
+	}
+	return billed, recorded
+}
+
+// RecordInvoice stores the invoice and marks its sessions as billed. It fails if the
+// number is taken or a session was billed in the meantime, so call it before handing
+// out the invoice.
+func (s *Storage) RecordInvoice(invoice *Invoice) error {
+	records, err := s.LoadInvoices()
+	if err != nil {
+		return err
+	}
+	invoiced := invoicedSessions(records)
+	for _, record := range records {
+		if record.Number == invoice.Number {
+			return fmt.Errorf("invoice %s exists already", invoice.Number)
+		}
+	}
+	for _, key := range invoice.SessionKeys {
+		if number, ok := invoiced[key]; ok {
+			return fmt.Errorf("a session was billed with invoice %s already", number)
+		}
+	}
+
+	records = append(records, InvoiceRecord{
+		Number:   invoice.Number,
+		Date:     invoice.Date.Format("2006-01-02"),
+		Client:   invoice.ClientID,
+		From:     invoice.From.Format("2006-01-02"),
+		To:       invoice.To.Format("2006-01-02"),
+		Currency: invoice.Currency,
+		Total:    invoice.Total,
+		Sessions: invoice.SessionKeys,
+	})
+	return s.saveInvoices(records)
+}
+
+// discardInvoice removes a recorded invoice that couldn't be written, so its sessions
+// can be billed again
+func (s *Storage) discardInvoice(number string) error {
+	records, err := s.LoadInvoices()
+	if err != nil {
+		return err
+	}
+	records = slices.DeleteFunc(records, func(record InvoiceRecord) bool { return record.Number == number })
+	return s.saveInvoices(records)
+}
+
+func (s *Storage) saveInvoices(records []InvoiceRecord) error {
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal invoices: %w", err)
+	}
+	return os.WriteFile(s.invoicesFile, data, 0644)
+}
+
+// formatMoney formats an amount in cents with thousands separators, e.g. "1,234.50 EUR"
+func formatMoney(cents int64, currency string) string {
+	sign := ""
+	if cents < 0 {
+		sign, cents = "-", -cents
+	}
+	units := fmt.Sprint(cents / 100)
+	for i := len(units) - 3; i > 0; i -= 3 {
+		units = units[:i] + "," + units[i:]
+	}
+	return fmt.Sprintf("%s%s.%02d %s", sign, units, cents%100, currency)
+}
+
+// formatHours formats a duration as decimal hours for invoices, e.g. "7.50"
+func formatHours(d time.Duration) string {
+	return fmt.Sprintf("%.2f", d.Hours())
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInvoices(t *testing.T) {
+	storage := newTestStorage(t)
+	zero := 0.0
+	config := &Config{WeeklyTargetHours: 40, Billing: &BillingConfig{
+		VATPercent:    19,
+		InvoicePrefix: "INV-",
+		Issuer:        "Jane Doe\nMain St 1",
+		Clients: map[string]BillingClient{
+			"acme": {Name: "ACME Corp", Address: "Road 2\n12345 City", Rate: 100, Projects: map[string]float64{"Website": 0, "Support | Ops": 80}},
+			"beta": {Name: "Beta Inc", Currency: "usd", VATPercent: &zero, Projects: map[string]float64{"App": 120}},
+		},
+	}}
+	if err := storage.SaveConfig(config); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2025, 3, 10, 9, 0, 0, 0, time.Local)
+	sessions := []Session{
+		{Date: "2025-03-10", Duration: 5 * 3600, BreakTime: 1800, Start: start, End: start.Add(5 * time.Hour), Project: "Website"},
+		{Date: "2025-03-11", Duration: 2 * 3600, Project: "Website"},
+		{Date: "2025-03-11", Duration: 5400, Project: "Support | Ops"},
+		{Date: "2025-03-12", Duration: 3 * 3600, Project: "App"},
+		{Date: "2025-03-12", Duration: 3600, Project: "Internal"},
+	}
+	if err := storage.appendSessionsToCSV(sessions); err != nil {
+		t.Fatal(err)
+	}
+
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.Local)
+	to := time.Date(2025, 3, 31, 0, 0, 0, 0, time.Local)
+	invoice, err := storage.PrepareInvoice("acme", from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	today, _ := storage.Today()
+	if expected := fmt.Sprintf("INV-%d-0001", today.Year()); invoice.Number != expected {
+		t.Errorf("Expected invoice %s, got %s", expected, invoice.Number)
+	}
+	if len(invoice.Lines) != 2 || invoice.Lines[1].Project != "Website" || invoice.Lines[1].Hours != 6*time.Hour+30*time.Minute || invoice.Lines[1].Sessions != 2 {
+		t.Fatalf("Unexpected lines: %+v", invoice.Lines)
+	}
+	// 6.5h × 100 + 1.5h × 80 = 770.00, plus 19% VAT
+	if invoice.Net != 77000 || invoice.VAT != 14630 || invoice.Total != 91630 || invoice.Currency != "EUR" {
+		t.Errorf("Unexpected totals: net %d, VAT %d, total %d %s", invoice.Net, invoice.VAT, invoice.Total, invoice.Currency)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteInvoiceMarkdown(&buf, invoice); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "| Support \\| Ops | 1 | 1.50 | 80.00 EUR | 120.00 EUR |") ||
+		!strings.Contains(buf.String(), "| **Total** | | | | **916.30 EUR** |") {
+		t.Errorf("Unexpected Markdown invoice:\n%s", buf.String())
+	}
+	buf.Reset()
+	if err := WriteInvoiceHTML(&buf, invoice); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "<div class=\"address\">ACME Corp<br>Road 2<br>12345 City</div>") {
+		t.Errorf("Expected the client address in the HTML invoice")
+	}
+
+	if err := storage.RecordInvoice(invoice); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.RecordInvoice(invoice); err == nil {
+		t.Error("Expected recording the invoice twice to fail")
+	}
+	if _, err := storage.PrepareInvoice("acme", from, to); err == nil {
+		t.Error("Expected no sessions left to bill")
+	}
+
+	// Another client uses its own currency and VAT rate and continues the numbering
+	output := filepath.Join(t.TempDir(), "beta.md")
+	var out bytes.Buffer
+	if err := runInvoiceCommand(storage, []string{"create", "-client", "beta", "-from", "2025-03-01", "-to", "2025-03-31", "-o", output}, &out); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "# Invoice INV-") || !strings.Contains(string(data), "| **Total** | | | | **360.00 USD** |") {
+		t.Errorf("Unexpected invoice file:\n%s", data)
+	}
+	records, err := storage.LoadInvoices()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || !strings.HasSuffix(records[1].Number, "-0002") || len(records[0].Sessions) != 3 {
+		t.Errorf("Unexpected invoice records: %+v", records)
+	}
+}
+
+func TestInvoiceDryRun(t *testing.T) {
+	storage := newTestStorage(t)
+	config := &Config{Billing: &BillingConfig{Clients: map[string]BillingClient{"acme": {Rate: 90, Projects: map[string]float64{"Website": 0}}}}}
+	if err := storage.SaveConfig(config); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.appendSessionsToCSV([]Session{{Date: "2025-03-10", Duration: 3600, Project: "Website"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := runInvoiceCommand(storage, []string{"create", "-client", "acme", "-from", "2025-03-01", "-dry-run"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "<td>Website</td><td>1</td><td>1.00</td><td>90.00 EUR</td><td>90.00 EUR</td>") {
+		t.Errorf("Expected the invoice on stdout, got:\n%s", out.String())
+	}
+	if records, _ := storage.LoadInvoices(); len(records) != 0 {
+		t.Errorf("Expected a dry run not to record the invoice, got %+v", records)
+	}
+	if err := runInvoiceCommand(storage, []string{"create", "-client", "nobody"}, &out); err == nil {
+		t.Error("Expected an unknown client to fail")
+	}
+}
+
+func TestBillingValidation(t *testing.T) {
+	high := 120.0
+	for _, billing := range []*BillingConfig{
+		{Currency: "Euro", Clients: map[string]BillingClient{"a": {Rate: 1, Projects: map[string]float64{"x": 0}}}},
+		{VATPercent: -1},
+		{Clients: map[string]BillingClient{"a": {Rate: 1}}},
+		{Clients: map[string]BillingClient{"a": {Projects: map[string]float64{"x": 0}}}},
+		{Clients: map[string]BillingClient{"a": {Rate: 1, VATPercent: &high, Projects: map[string]float64{"x": 0}}}},
+		{Clients: map[string]BillingClient{
+			"a": {Rate: 1, Projects: map[string]float64{"x": 0}},
+			"b": {Rate: 1, Projects: map[string]float64{"x": 0}},
+		}},
+	} {
+		if err := (&Config{Billing: billing}).Validate(); err == nil {
+			t.Errorf("Expected %+v to fail validation", billing)
+		}
+	}
+
+	if got := formatMoney(123456789, "EUR"); got != "1,234,567.89 EUR" {
+		t.Errorf("Expected thousands separators, got %s", got)
+	}
+	if got := formatMoney(-5, "USD"); got != "-0.05 USD" {
+		t.Errorf("Expected a negative amount, got %s", got)
+	}
+}
+
+func TestInvoiceRecordedBeforeWriting(t *testing.T) {
+	storage := newTestStorage(t)
+	config := &Config{Billing: &BillingConfig{Clients: map[string]BillingClient{"acme": {Rate: 90, Projects: map[string]float64{"Website": 0}}}}}
+	if err := storage.SaveConfig(config); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.appendSessionsToCSV([]Session{{Date: "2025-03-10", Duration: 3600, Project: "Website"}}); err != nil {
+		t.Fatal(err)
+	}
+	args := []string{"create", "-client", "acme", "-from", "2025-03-01"}
+	var out bytes.Buffer
+
+	// An invoice that can't be written is not recorded
+	missingDir := filepath.Join(t.TempDir(), "missing", "invoice.html")
+	if err := runInvoiceCommand(storage, append(args, "-o", missingDir), &out); err == nil {
+		t.Fatal("Expected writing to a missing directory to fail")
+	}
+	if records, _ := storage.LoadInvoices(); len(records) != 0 {
+		t.Errorf("Expected the unwritten invoice to be discarded, got %+v", records)
+	}
+
+	// An invoice that can't be recorded is not written
+	output := filepath.Join(t.TempDir(), "invoice.html")
+	invoicesFile := storage.invoicesFile
+	storage.invoicesFile = filepath.Join(t.TempDir(), "missing", "invoices.json")
+	if err := runInvoiceCommand(storage, append(args, "-o", output), &out); err == nil {
+		t.Fatal("Expected recording to fail")
+	}
+	if _, err := os.Stat(output); !os.IsNotExist(err) {
+		t.Error("Expected no invoice file when recording fails")
+	}
+
+	storage.invoicesFile = invoicesFile
+	if err := runInvoiceCommand(storage, append(args, "-o", output), &out); err != nil {
+		t.Fatal(err)
+	}
+	if records, _ := storage.LoadInvoices(); len(records) != 1 {
+		t.Errorf("Expected one recorded invoice, got %+v", records)
+	}
+}
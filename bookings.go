@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Booking kinds
+const (
+	bookingLieu       = "lieu"       // time in lieu, derived from "lieu" absences
+	bookingPayout     = "payout"     // overtime paid out, reduces the balance
+	bookingCorrection = "correction" // manual correction in either direction
+)
+
+var bookingsCSVHeader = []string{"date", "kind", "amount_s", "reason"}
+
+// Booking changes the flextime balance on a date independently of the time worked
+type Booking struct {
+	Date string
+	Kind string
+	// Amount is added to the balance; negative amounts reduce it
+	Amount time.Duration
+	Reason string
+}
+
+// lieuBookings books the credited target of every time-in-lieu absence against the
+// balance, so a day off taken for overtime reduces it by the day's target
+func lieuBookings(config *Config, absences Absences) []Booking {
+	var bookings []Booking
+	for date, absence := range absences {
+		if absence.Type != absenceLieu {
+			continue
+		}
+		day, err := time.ParseInLocation("2006-01-02", date, time.Local)
+		if err != nil {
+			continue
+		}
+		if credit := config.DailyTarget(day) - absences.Target(config, day); credit > 0 {
+			bookings = append(bookings, Booking{Date: date, Kind: bookingLieu, Amount: -credit, Reason: absence.Note})
+		}
+	}
+	return bookings
+}
+
+// sortBookings orders bookings by date, keeping the order of bookings on the same day
+func sortBookings(bookings []Booking) {
+	sort.SliceStable(bookings, func(i, j int) bool { return bookings[i].Date < bookings[j].Date })
+}
+
+// LoadBookings returns the manual bookings (payouts and corrections) sorted by date
+func (s *Storage) LoadBookings() ([]Booking, error) {
+	if s.bookingsFile == "" {
+		return nil, nil
+	}
+	file, err := os.Open(s.bookingsFile)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	if _, err := reader.Read(); err != nil {
+		if err == io.EOF {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var bookings []Booking
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) < 3 {
+			continue // Skip invalid records
+		}
+		seconds, err := strconv.ParseInt(record[2], 10, 64)
+		if err != nil {
+			continue
+		}
+		booking := Booking{Date: record[0], Kind: record[1], Amount: time.Duration(seconds) * time.Second}
+		if len(record) >= 4 {
+			booking.Reason = record[3]
+		}
+		bookings = append(bookings, booking)
+	}
+	sortBookings(bookings)
+	return bookings, nil
+}
+
+// AddBooking records a payout or correction. Payouts must reduce the balance and
+// corrections need a reason, so the ledger explains every change.
+func (s *Storage) AddBooking(booking Booking) error {
+	if _, err := time.Parse("2006-01-02", booking.Date); err != nil {
+		return fmt.Errorf("invalid date %q", booking.Date)
+	}
+	booking.Amount = booking.Amount.Round(time.Second)
+	switch booking.Kind {
+	case bookingPayout:
+		if booking.Amount >= 0 {
+			return fmt.Errorf("a payout must reduce the balance")
+		}
+	case bookingCorrection:
+		if booking.Amount == 0 {
+			return fmt.Errorf("a correction must change the balance")
+		}
+		if booking.Reason == "" {
+			return fmt.Errorf("a correction needs a reason")
+		}
+	default:
+		return fmt.Errorf("unknown booking kind %q, use %s or %s", booking.Kind, bookingPayout, bookingCorrection)
+	}
+
+	bookings, err := s.LoadBookings()
+	if err != nil {
+		return err
+	}
+	bookings = append(bookings, booking)
+	sortBookings(bookings)
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.Write(bookingsCSVHeader)
+	for _, b := range bookings {
+		writer.Write([]string{b.Date, b.Kind, strconv.FormatInt(int64(b.Amount/time.Second), 10), b.Reason})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return err
+	}
+	return os.WriteFile(s.bookingsFile, buf.Bytes(), 0644)
+}
+
+// allBookings returns the manual bookings and those derived from time-in-lieu absences, sorted by date
+func (s *Storage) allBookings(config *Config, absences Absences) ([]Booking, error) {
+	bookings, err := s.LoadBookings()
+	if err != nil {
+		return nil, err
+	}
+	bookings = append(bookings, lieuBookings(config, absences)...)
+	sortBookings(bookings)
+	return bookings, nil
+}
+
+// LedgerEntry is a booking with the sum of all counted bookings up to and including it
+type LedgerEntry struct {
+	Booking
+	// Counted is false for bookings outside the balance period (before its start or in the future)
+	Counted bool
+	Total   time.Duration
+}
+
+// Ledger lists every booking together with the flextime balance up to end
+type Ledger struct {
+	Entries  []LedgerEntry
+	Flextime *FlextimeReport
+}
+
+// Ledger returns every booking and the flextime balance up to the given day
+func (s *Storage) Ledger(end time.Time) (*Ledger, error) {
+	flextime, err := s.Flextime(end)
+	if err != nil {
+		return nil, err
+	}
+	config, err := s.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	absences, err := s.LoadAbsences()
+	if err != nil {
+		return nil, err
+	}
+	bookings, err := s.allBookings(config, absences)
+	if err != nil {
+		return nil, err
+	}
+
+	ledger := &Ledger{Flextime: flextime}
+	var total time.Duration
+	for _, booking := range bookings {
+		entry := LedgerEntry{Booking: booking, Counted: flextime.counts(booking)}
+		if entry.Counted {
+			total += booking.Amount
+		}
+		entry.Total = total
+		ledger.Entries = append(ledger.Entries, entry)
+	}
+	return ledger, nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBookingsInFlextime(t *testing.T) {
+	storage := newTestStorage(t)
+	if err := storage.SaveConfig(&Config{WeeklyTargetHours: 40, FlextimeStart: "2025-03-10"}); err != nil {
+		t.Fatal(err)
+	}
+	sessions := []Session{
+		{Date: "2025-03-10", Duration: 10 * 3600}, // +2:00
+		{Date: "2025-03-11", Duration: 10 * 3600}, // +2:00
+		{Date: "2025-03-13", Duration: 8 * 3600},
+		{Date: "2025-03-14", Duration: 8 * 3600},
+	}
+	if err := storage.appendSessionsToCSV(sessions); err != nil {
+		t.Fatal(err)
+	}
+	wednesday := time.Date(2025, 3, 12, 0, 0, 0, 0, time.Local)
+	if _, err := storage.AddAbsence(wednesday, wednesday, absenceLieu, false, "overtime"); err != nil {
+		t.Fatal(err)
+	}
+	for _, booking := range []Booking{
+		{Date: "2025-03-13", Kind: bookingPayout, Amount: -time.Hour, Reason: "March payroll"},
+		{Date: "2025-03-14", Kind: bookingCorrection, Amount: 30 * time.Minute, Reason: "forgot to start the timer"},
+		{Date: "2025-03-20", Kind: bookingCorrection, Amount: time.Hour, Reason: "after the balance period"},
+	} {
+		if err := storage.AddBooking(booking); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	friday := time.Date(2025, 3, 14, 0, 0, 0, 0, time.Local)
+	report, err := storage.Flextime(friday)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// +2:00 +2:00 -8:00 (time in lieu) -1:00 (payout) +0:30 (correction)
+	if report.Balance != -4*time.Hour-30*time.Minute {
+		t.Errorf("Expected a balance of -4:30, got %v", report.Balance)
+	}
+	if report.Weeks[0].Booked != -8*time.Hour-30*time.Minute {
+		t.Errorf("Expected -8:30 booked in the week, got %v", report.Weeks[0].Booked)
+	}
+
+	ledger, err := storage.Ledger(friday)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ledger.Entries) != 4 {
+		t.Fatalf("Expected 4 ledger entries, got %+v", ledger.Entries)
+	}
+	if first := ledger.Entries[0]; first.Kind != bookingLieu || first.Amount != -8*time.Hour || first.Reason != "overtime" {
+		t.Errorf("Expected the time in lieu first, got %+v", first)
+	}
+	if last := ledger.Entries[3]; last.Counted || last.Total != -8*time.Hour-30*time.Minute {
+		t.Errorf("Expected the future correction not to be counted, got %+v", last)
+	}
+
+	var out bytes.Buffer
+	if err := runBalanceCommand(storage, []string{"ledger"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "2025-03-13  payout        -1:00:00    -9:00:00  March payroll") {
+		t.Errorf("Expected the payout in the ledger, got:\n%s", out.String())
+	}
+}
+
+func TestAddBookingValidation(t *testing.T) {
+	storage := newTestStorage(t)
+	tests := []Booking{
+		{Date: "2025-03-13", Kind: bookingPayout, Amount: time.Hour},
+		{Date: "2025-03-13", Kind: bookingCorrection, Amount: time.Hour},
+		{Date: "2025-03-13", Kind: bookingLieu, Amount: -time.Hour, Reason: "booked through absences"},
+		{Date: "13.03.2025", Kind: bookingCorrection, Amount: time.Hour, Reason: "invalid date"},
+	}
+	for _, booking := range tests {
+		if err := storage.AddBooking(booking); err == nil {
+			t.Errorf("Expected %+v to be rejected", booking)
+		}
+	}
+
+	var out bytes.Buffer
+	if err := runBalanceCommand(storage, []string{"payout", "-hours", "2.5", "2025-03-31"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	bookings, err := storage.LoadBookings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bookings) != 1 || bookings[0].Amount != -150*time.Minute || bookings[0].Date != "2025-03-31" {
+		t.Errorf("Expected a payout of 2:30 on 2025-03-31, got %+v", bookings)
+	}
+}
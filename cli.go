@@ -0,0 +1,796 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// cliCommand is a subcommand run instead of the GUI, e.g. `timetracker import`
+type cliCommand struct {
+	usage string
+	run   func(storage *Storage, args []string, out io.Writer) error
+}
+
+var cliCommands = map[string]cliCommand{
+	"import":     {"import [-format toggl|clockify|harvest] [-dry-run] FILE...", runImportCommand},
+	"export-ics": {"export-ics [-from YYYY-MM-DD] [-to YYYY-MM-DD] [-o FILE]", runExportICSCommand},
+	"doctor":     {"doctor [-fix]", runDoctorCommand},
+	"tui":        {"tui", runTUICommand},
+	"status":     {"status", runStatusCommand},
+	"flextime":   {"flextime [-weeks N]", runFlextimeCommand},
+	"weeks":      {"weeks [-n N]", runWeeksCommand},
+	"stats":      {"stats [-from YYYY-MM-DD] [-to YYYY-MM-DD]", runStatsCommand},
+	"compliance": {"compliance [-from YYYY-MM-DD] [-to YYYY-MM-DD]", runComplianceCommand},
+	"days":       {"days [-from YYYY-MM-DD] [-to YYYY-MM-DD]", runDaysCommand},
+	"holidays":   {"holidays [-year YYYY] [-calendar de-by]", runHolidaysCommand},
+	"absence":    {"absence add -type vacation|sick|training|unpaid|lieu [-half] [-note TEXT] FROM [TO] | remove FROM [TO] | list [-year YYYY]", runAbsenceCommand},
+	"contract":   {"contract add -from YYYY-MM-DD [-to YYYY-MM-DD] [-weekly HOURS | -days mon=8,thu=4] [-note TEXT] | list", runContractCommand},
+	"balance":    {"balance payout -hours H [-reason TEXT] [DATE] | correct -hours [-]H -reason TEXT [DATE] | ledger", runBalanceCommand},
+	"report":     {"report [-from YYYY-MM-DD] [-to YYYY-MM-DD] [-o FILE.html]", runReportCommand},
+	"invoice":    {"invoice create -client ID [-from YYYY-MM-DD] [-to YYYY-MM-DD] [-o FILE.html|FILE.md] [-dry-run] | list", runInvoiceCommand},
+	"timesheet":  {"timesheet [-month YYYY-MM] [-o FILE.pdf]", runTimesheetCommand},
+	"query":      {"query EXPRESSION  (e.g. 'work > 6h and break = 0 and weekday = fri')", runQueryCommand},
+}
+
+// runCLI executes the subcommand named in args and returns the process exit code
+func runCLI(storage *Storage, args []string, out io.Writer) int {
+	command, ok := cliCommands[args[0]]
+	if !ok {
+		printCLIUsage(out)
+		return 2
+	}
+	if err := command.run(storage, args[1:], out); err != nil {
+		fmt.Fprintf(out, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func printCLIUsage(out io.Writer) {
+	names := make([]string, 0, len(cliCommands))
+	for name := range cliCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(out, "Usage: timetracker [COMMAND]")
+	fmt.Fprintln(out, "Without a command the GUI is started. Commands:")
+	for _, name := range names {
+		fmt.Fprintf(out, "  timetracker %s\n", cliCommands[name].usage)
+	}
+}
+
+// formatSessionLine formats a session as a single line for listings
+func formatSessionLine(session Session) string {
+	start, end := "--:--", "--:--"
+	if !session.Start.IsZero() {
+		start = session.Start.Format("15:04")
+	}
+	if !session.End.IsZero() {
+		end = session.End.Format("15:04")
+	}
+	line := fmt.Sprintf("%s %s-%s  work %s  break %s",
+		session.Date, start, end,
+		formatDuration(time.Duration(session.Duration-session.BreakTime)*time.Second),
+		formatDuration(time.Duration(session.BreakTime)*time.Second))
+	if session.Project != "" {
+		line += "  [" + session.Project + "]"
+	}
+	if session.Description != "" {
+		line += "  " + session.Description
+	}
+	return line
+}
+
+func runImportCommand(storage *Storage, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	flags.SetOutput(out)
+	format := flags.String("format", "", "export format (detected from the header if empty)")
+	dryRun := flags.Bool("dry-run", false, "preview the import without storing anything")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return fmt.Errorf("no file given")
+	}
+
+	for _, path := range flags.Args() {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		result, err := ParseImport(file, *format)
+		file.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if err := storage.ImportSessions(result, *dryRun); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		fmt.Fprintf(out, "%s (%s):\n", path, result.Format)
+		for _, session := range result.Sessions {
+			fmt.Fprintln(out, "  "+formatSessionLine(session))
+		}
+		for _, skipped := range result.Skipped {
+			fmt.Fprintf(out, "  skipped %s\n", skipped)
+		}
+
+		verb := "imported"
+		if *dryRun {
+			verb = "would import"
+		}
+		fmt.Fprintf(out, "%s %d sessions, %d duplicates, %d skipped\n", verb, len(result.Sessions), result.Duplicates, len(result.Skipped))
+	}
+	return nil
+}
+
+func runExportICSCommand(storage *Storage, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("export-ics", flag.ContinueOnError)
+	flags.SetOutput(out)
+	from := flags.String("from", "", "first date to export")
+	to := flags.String("to", "", "last date to export")
+	output := flags.String("o", "", "output file (stdout if empty)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	start, end, err := parseDateRange(*from, *to)
+	if err != nil {
+		return err
+	}
+	sessions, err := storage.LoadSessions(start, end)
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		return WriteICS(out, sessions)
+	}
+	file, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := WriteICS(file, sessions); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "exported %d sessions to %s\n", len(sessions), *output)
+	return nil
+}
+
+func runReportCommand(storage *Storage, args []string, out io.Writer) error {
+	today, err := storage.Today()
+	if err != nil {
+		return err
+	}
+	flags := flag.NewFlagSet("report", flag.ContinueOnError)
+	flags.SetOutput(out)
+	from := flags.String("from", today.Format("2006-01")+"-01", "first date of the report")
+	to := flags.String("to", "", "last date of the report (today if empty)")
+	output := flags.String("o", "", "output file (stdout if empty)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	start, end, err := parseDateRange(*from, *to)
+	if err != nil {
+		return err
+	}
+	report, err := storage.PeriodReport(start, end)
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		return WriteHTMLReport(out, report)
+	}
+	file, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := WriteHTMLReport(file, report); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "wrote report for %s to %s to %s\n", report.From.Format("2006-01-02"), report.To.Format("2006-01-02"), *output)
+	return nil
+}
+
+func runTimesheetCommand(storage *Storage, args []string, out io.Writer) error {
+	today, err := storage.Today()
+	if err != nil {
+		return err
+	}
+	flags := flag.NewFlagSet("timesheet", flag.ContinueOnError)
+	flags.SetOutput(out)
+	month := flags.String("month", today.Format("2006-01"), "month of the timesheet")
+	output := flags.String("o", "", "output file (stdout if empty)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	date, err := time.ParseInLocation("2006-01", *month, time.Local)
+	if err != nil {
+		return fmt.Errorf("invalid month %q", *month)
+	}
+	sheet, err := storage.Timesheet(date.Year(), date.Month())
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		return WriteTimesheetPDF(out, sheet)
+	}
+	file, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := WriteTimesheetPDF(file, sheet); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "wrote timesheet for %s to %s\n", *month, *output)
+	return nil
+}
+
+func runDoctorCommand(storage *Storage, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	flags.SetOutput(out)
+	fix := flags.Bool("fix", false, "apply the automatic fixes (a .bak copy of each changed file is kept)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	report, err := storage.Diagnose()
+	if err != nil {
+		return err
+	}
+	for _, issue := range report.Issues {
+		fmt.Fprintln(out, issue)
+	}
+	if len(report.Issues) == 0 {
+		fmt.Fprintln(out, "no problems found")
+		return nil
+	}
+
+	if !*fix {
+		fmt.Fprintf(out, "%d problems found, %d can be fixed with -fix\n", len(report.Issues), report.Fixable())
+		return fmt.Errorf("data has problems")
+	}
+	if err := storage.Repair(report); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "fixed %d of %d problems\n", report.Fixable(), len(report.Issues))
+	if report.Fixable() < len(report.Issues) {
+		return fmt.Errorf("some problems need manual fixing")
+	}
+	return nil
+}
+
+func runTUICommand(storage *Storage, args []string, out io.Writer) error {
+	timer, err := storage.LoadTimer()
+	if err != nil {
+		return fmt.Errorf("failed to load timer state: %w", err)
+	}
+	return NewTUI(timer, storage).Run()
+}
+
+func runQueryCommand(storage *Storage, args []string, out io.Writer) error {
+	query, err := ParseQuery(strings.Join(args, " "))
+	if err != nil {
+		fmt.Fprintln(out, "Fields:")
+		for _, field := range queryFields {
+			fmt.Fprintf(out, "  %-9s %s\n", field.name, field.help)
+		}
+		fmt.Fprintln(out, "Operators: = != < <= > >= ~, combined with and, or, not and parentheses")
+		return err
+	}
+
+	var count int
+	var work, breaks time.Duration
+	err = storage.QuerySessions(query, func(session Session) bool {
+		fmt.Fprintln(out, formatSessionLine(session))
+		count++
+		work += time.Duration(session.Duration-session.BreakTime) * time.Second
+		breaks += time.Duration(session.BreakTime) * time.Second
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "%d sessions, work %s, break %s\n", count, formatDuration(work), formatDuration(breaks))
+	return nil
+}
+
+func runFlextimeCommand(storage *Storage, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("flextime", flag.ContinueOnError)
+	flags.SetOutput(out)
+	weeks := flags.Int("weeks", 0, "only list the last N weeks (all if 0)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	today, err := storage.Today()
+	if err != nil {
+		return err
+	}
+	report, err := storage.Flextime(today)
+	if err != nil {
+		return err
+	}
+
+	listed := report.Weeks
+	if *weeks > 0 && len(listed) > *weeks {
+		listed = listed[len(listed)-*weeks:]
+	}
+	fmt.Fprintf(out, "%-9s %10s %10s %11s %11s\n", "Week", "Worked", "Target", "Booked", "Delta")
+	for _, week := range listed {
+		fmt.Fprintf(out, "%d-W%02d %10s %10s %11s %11s\n", week.Year, week.Week,
+			formatDuration(week.Worked), formatDuration(week.Target), formatSignedDuration(week.Booked), formatSignedDuration(week.Delta()))
+	}
+	if !report.Start.IsZero() {
+		fmt.Fprintf(out, "Balance since %s: %s\n", report.Start.Format("2006-01-02"), formatSignedDuration(report.Balance))
+	}
+	return nil
+}
+
+func runWeeksCommand(storage *Storage, args []string, out io.Writer) error {
+	today, err := storage.Today()
+	if err != nil {
+		return err
+	}
+	flags := flag.NewFlagSet("weeks", flag.ContinueOnError)
+	flags.SetOutput(out)
+	n := flags.Int("n", weeklyDefaultWeeks, "number of weeks up to the current one")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	weeks, err := storage.WeeklySummaries(*n, today)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "%-9s %-10s %10s %10s %10s\n", "Week", "Start", "Worked", "Breaks", "Target")
+	for _, week := range weeks {
+		fmt.Fprintf(out, "%d-W%02d %-10s %10s %10s %10s\n", week.Year, week.Week, week.Start.Format("2006-01-02"),
+			formatDuration(week.Worked), formatDuration(week.Break), formatDuration(week.Target))
+	}
+	return nil
+}
+
+func runStatsCommand(storage *Storage, args []string, out io.Writer) error {
+	today, err := storage.Today()
+	if err != nil {
+		return err
+	}
+	flags := flag.NewFlagSet("stats", flag.ContinueOnError)
+	flags.SetOutput(out)
+	from := flags.String("from", today.AddDate(0, 0, 1-statsDefaultDays).Format("2006-01-02"), "first date of the period")
+	to := flags.String("to", "", "last date of the period (today if empty)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	start, end, err := parseDateRange(*from, *to)
+	if err != nil {
+		return err
+	}
+	stats, err := storage.WorkStats(start, end)
+	if err != nil {
+		return err
+	}
+	for _, row := range stats.Rows() {
+		fmt.Fprintf(out, "%-24s %s\n", row[0]+":", row[1])
+	}
+	return nil
+}
+
+func runStatusCommand(storage *Storage, args []string, out io.Writer) error {
+	timer, err := storage.LoadTimer()
+	if err != nil {
+		return fmt.Errorf("failed to load timer state: %w", err)
+	}
+
+	state := tuiTextNotRunning
+	if timer.IsOnBreak {
+		state = tuiTextOnBreak
+	} else if timer.IsRunning {
+		state = tuiTextRunning
+	}
+	fmt.Fprintln(out, state)
+	fmt.Fprintf(out, "%s%s\n", formatTodaySession, formatDuration(timer.GetTodaySessionTime()))
+	fmt.Fprintf(out, "%s%s\n", formatBreakTime, formatDuration(timer.GetCurrentBreakTime()))
+	fmt.Fprintf(out, "%s%s\n", formatDailyTotal, formatDuration(timer.GetDailyTime()))
+	fmt.Fprintf(out, "%s%s\n", formatFirstStart, timer.GetDayFirstStartTime())
+	fmt.Fprintf(out, "%s%s\n", formatLeaveAt, timer.GetLeaveAtTime())
+	fmt.Fprintf(out, "%s%s\n", formatRest, timer.GetRestTime())
+	fmt.Fprintf(out, "%s%s\n", formatWeeklyTotal, formatDuration(timer.GetWeeklyTime()))
+	fmt.Fprintf(out, "%s%s\n", formatFlextime, formatSignedDuration(timer.GetFlextimeBalance()))
+	fmt.Fprintf(out, "%s%s\n", formatMonthlyTotal, formatDuration(timer.GetMonthlyTime()))
+	return nil
+}
+
+func runComplianceCommand(storage *Storage, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("compliance", flag.ContinueOnError)
+	flags.SetOutput(out)
+	from := flags.String("from", "", "first date to check")
+	to := flags.String("to", "", "last date to check")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	start, end, err := parseDateRange(*from, *to)
+	if err != nil {
+		return err
+	}
+	violations, err := storage.Compliance(start, end)
+	if err != nil {
+		return err
+	}
+	for _, violation := range violations {
+		fmt.Fprintln(out, violation)
+	}
+	fmt.Fprintf(out, "%d violations\n", len(violations))
+	return nil
+}
+
+func runHolidaysCommand(storage *Storage, args []string, out io.Writer) error {
+	config, err := storage.LoadConfig()
+	if err != nil {
+		return err
+	}
+	flags := flag.NewFlagSet("holidays", flag.ContinueOnError)
+	flags.SetOutput(out)
+	year := flags.Int("year", time.Now().Year(), "year to list")
+	calendar := flags.String("calendar", config.Holidays, "holiday calendar (the configured one if empty): "+holidayCalendarNames())
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *calendar == "" {
+		return fmt.Errorf("no holiday calendar configured, set \"holidays\" in config.json or use -calendar")
+	}
+
+	holidays, err := Holidays(*calendar, *year)
+	if err != nil {
+		return err
+	}
+	for _, holiday := range holidays {
+		fmt.Fprintf(out, "%s  %s\n", holiday.Date.Format("Mon 2006-01-02"), holiday.Name)
+	}
+	return nil
+}
+
+func runAbsenceCommand(storage *Storage, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing subcommand: add, remove or list")
+	}
+	switch args[0] {
+	case "add":
+		flags := flag.NewFlagSet("absence add", flag.ContinueOnError)
+		flags.SetOutput(out)
+		absenceType := flags.String("type", absenceVacation, "absence type: "+strings.Join(absenceTypes, ", "))
+		half := flags.Bool("half", false, "half day")
+		note := flags.String("note", "", "optional note")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		kind, err := parseAbsenceType(*absenceType)
+		if err != nil {
+			return err
+		}
+		from, to, err := parseAbsenceDates(flags.Args())
+		if err != nil {
+			return err
+		}
+		added, err := storage.AddAbsence(from, to, kind, *half, *note)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "recorded %s on %d days\n", kind, added)
+	case "remove":
+		from, to, err := parseAbsenceDates(args[1:])
+		if err != nil {
+			return err
+		}
+		removed, err := storage.RemoveAbsences(from, to)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "removed %d absences\n", removed)
+	case "list":
+		flags := flag.NewFlagSet("absence list", flag.ContinueOnError)
+		flags.SetOutput(out)
+		year := flags.Int("year", time.Now().Year(), "year to list")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		absences, err := storage.LoadAbsences()
+		if err != nil {
+			return err
+		}
+		for _, absence := range absences.inYear(*year) {
+			fmt.Fprintf(out, "%s  %-20s %s\n", absence.Date, absence.Label(), absence.Note)
+		}
+		vacation, err := storage.VacationBalance(*year)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, formatVacationBalance(vacation))
+	default:
+		return fmt.Errorf("unknown subcommand %q, use add, remove or list", args[0])
+	}
+	return nil
+}
+
+func runContractCommand(storage *Storage, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing subcommand: add or list")
+	}
+	switch args[0] {
+	case "add":
+		flags := flag.NewFlagSet("contract add", flag.ContinueOnError)
+		flags.SetOutput(out)
+		from := flags.String("from", "", "first day of the contract period")
+		to := flags.String("to", "", "last day of the contract period (open if empty)")
+		weekly := flags.Float64("weekly", 0, "weekly target hours, spread over Monday to Friday")
+		days := flags.String("days", "", "target hours per weekday, e.g. mon=8,tue=8,wed=8,thu=4")
+		note := flags.String("note", "", "optional note")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		if *from == "" {
+			return fmt.Errorf("missing -from date")
+		}
+		period := ContractPeriod{From: *from, To: *to, WeeklyTargetHours: *weekly, Note: *note}
+		if *days != "" {
+			hours, err := parseWeekdayHours(*days)
+			if err != nil {
+				return err
+			}
+			period.WeekdayTargetHours = hours
+		}
+		if err := storage.AddContract(period); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "contract from %s: %s\n", period.From, formatSchedule(period.WeeklyTargetHours, period.WeekdayTargetHours))
+	case "list":
+		config, err := storage.LoadConfig()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "%-10s  %-10s  %s\n", "default", "", formatSchedule(config.WeeklyTargetHours, config.WeekdayTargetHours))
+		for _, period := range config.Contracts {
+			to := period.To
+			if to == "" {
+				to = "open"
+			}
+			fmt.Fprintf(out, "%-10s  %-10s  %s  %s\n", period.From, to,
+				formatSchedule(period.WeeklyTargetHours, period.WeekdayTargetHours), period.Note)
+		}
+	default:
+		return fmt.Errorf("unknown subcommand %q, use add or list", args[0])
+	}
+	return nil
+}
+
+func runBalanceCommand(storage *Storage, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing subcommand: payout, correct or ledger")
+	}
+	today, err := storage.Today()
+	if err != nil {
+		return err
+	}
+	switch args[0] {
+	case "payout", "correct":
+		flags := flag.NewFlagSet("balance "+args[0], flag.ContinueOnError)
+		flags.SetOutput(out)
+		hours := flags.Float64("hours", 0, "hours to book; payouts are subtracted from the balance")
+		reason := flags.String("reason", "", "reason for the booking")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		booking := Booking{Date: today.Format("2006-01-02"), Kind: bookingCorrection, Amount: hoursToDuration(*hours), Reason: *reason}
+		if args[0] == "payout" {
+			booking.Kind, booking.Amount = bookingPayout, -booking.Amount
+		}
+		switch flags.NArg() {
+		case 0:
+		case 1:
+			booking.Date = flags.Arg(0)
+		default:
+			return fmt.Errorf("expected at most one date")
+		}
+		if err := storage.AddBooking(booking); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "booked %s %s on %s\n", booking.Kind, formatSignedDuration(booking.Amount), booking.Date)
+	case "ledger":
+		ledger, err := storage.Ledger(today)
+		if err != nil {
+			return err
+		}
+		for _, entry := range ledger.Entries {
+			fmt.Fprintln(out, formatLedgerEntry(entry))
+		}
+		fmt.Fprintln(out, formatLedgerTotals(ledger))
+	default:
+		return fmt.Errorf("unknown subcommand %q, use payout, correct or ledger", args[0])
+	}
+	return nil
+}
+
+func runInvoiceCommand(storage *Storage, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing subcommand: create or list")
+	}
+	switch args[0] {
+	case "create":
+		today, err := storage.Today()
+		if err != nil {
+			return err
+		}
+		flags := flag.NewFlagSet("invoice create", flag.ContinueOnError)
+		flags.SetOutput(out)
+		client := flags.String("client", "", "client ID from the billing settings")
+		from := flags.String("from", today.Format("2006-01")+"-01", "first date to bill")
+		to := flags.String("to", "", "last date to bill (today if empty)")
+		output := flags.String("o", "", "output file, Markdown if it ends in .md (HTML on stdout if empty)")
+		dryRun := flags.Bool("dry-run", false, "write the invoice without marking its sessions as billed")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		if *client == "" {
+			return fmt.Errorf("missing -client")
+		}
+
+		start, end, err := parseDateRange(*from, *to)
+		if err != nil {
+			return err
+		}
+		invoice, err := storage.PrepareInvoice(*client, start, end)
+		if err != nil {
+			return err
+		}
+		// Record first, so no invoice is handed out whose sessions are still billable
+		if !*dryRun {
+			if err := storage.RecordInvoice(invoice); err != nil {
+				return err
+			}
+		}
+		if *output == "" {
+			err = WriteInvoiceHTML(out, invoice)
+		} else {
+			err = writeInvoiceFile(*output, invoice)
+		}
+		if err != nil {
+			if !*dryRun {
+				if discardErr := storage.discardInvoice(invoice.Number); discardErr != nil {
+					return fmt.Errorf("%w; invoice %s stays recorded: %v", err, invoice.Number, discardErr)
+				}
+			}
+			return err
+		}
+		if *output != "" && !*dryRun {
+			billed, recorded := invoice.BilledHours()
+			fmt.Fprintf(out, "wrote invoice %s over %s to %s (%s h billed, %s h recorded)\n", invoice.Number,
+				formatMoney(invoice.Total, invoice.Currency), *output, formatHours(billed), formatHours(recorded))
+		}
+	case "list":
+		records, err := storage.LoadInvoices()
+		if err != nil {
+			return err
+		}
+		for _, record := range records {
+			fmt.Fprintf(out, "%-14s %s  %-12s %s to %s  %4d sessions  %s\n", record.Number, record.Date, record.Client,
+				record.From, record.To, len(record.Sessions), formatMoney(record.Total, record.Currency))
+		}
+	default:
+		return fmt.Errorf("unknown subcommand %q, use create or list", args[0])
+	}
+	return nil
+}
+
+// writeInvoiceFile writes the invoice to the named file, see writeInvoice; the file is
+// removed if writing fails
+func writeInvoiceFile(name string, invoice *Invoice) error {
+	file, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	err = writeInvoice(file, name, invoice)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(name)
+	}
+	return err
+}
+
+// formatLedgerEntry formats a booking with the running total for the ledger
+func formatLedgerEntry(entry LedgerEntry) string {
+	total := formatSignedDuration(entry.Total)
+	if !entry.Counted {
+		total = "not counted"
+	}
+	return fmt.Sprintf("%s  %-10s %11s %11s  %s", entry.Date, entry.Kind, formatSignedDuration(entry.Amount), total, entry.Reason)
+}
+
+// formatLedgerTotals splits the flextime balance into worked time against the target and bookings
+func formatLedgerTotals(ledger *Ledger) string {
+	var booked time.Duration
+	for _, week := range ledger.Flextime.Weeks {
+		booked += week.Booked
+	}
+	return fmt.Sprintf("Worked - target: %s  Bookings: %s  Balance: %s",
+		formatSignedDuration(ledger.Flextime.Balance-booked), formatSignedDuration(booked), formatSignedDuration(ledger.Flextime.Balance))
+}
+
+// parseAbsenceDates parses "FROM [TO]"; a single date gives a one-day range
+func parseAbsenceDates(args []string) (time.Time, time.Time, error) {
+	if len(args) == 0 || len(args) > 2 {
+		return time.Time{}, time.Time{}, fmt.Errorf("expected FROM [TO] dates (YYYY-MM-DD)")
+	}
+	to := args[0]
+	if len(args) == 2 {
+		to = args[1]
+	}
+	from, end, err := parseDateRange(args[0], to)
+	if err == nil && end.Before(from) {
+		err = fmt.Errorf("%s is before %s", to, args[0])
+	}
+	return from, end, err
+}
+
+// formatVacationBalance describes the vacation days taken and left
+func formatVacationBalance(vacation VacationBalance) string {
+	if vacation.Allowance == 0 {
+		return fmt.Sprintf("Vacation %d: %g days taken", vacation.Year, vacation.Taken)
+	}
+	return fmt.Sprintf("Vacation %d: %g of %g days taken, %g remaining",
+		vacation.Year, vacation.Taken, vacation.Allowance, vacation.Remaining())
+}
+
+func runDaysCommand(storage *Storage, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("days", flag.ContinueOnError)
+	flags.SetOutput(out)
+	from := flags.String("from", "", "first date to list")
+	to := flags.String("to", "", "last date to list")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	start, end, err := parseDateRange(*from, *to)
+	if err != nil {
+		return err
+	}
+	days, err := storage.WorkDays(start, end)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "%-10s  %-5s  %-5s  %8s  %8s  %8s\n", "Date", "Start", "End", "Worked", "Break", "Rest")
+	for _, day := range days {
+		rest := "-"
+		if day.Rest != 0 {
+			rest = formatDuration(day.Rest)
+		}
+		fmt.Fprintf(out, "%-10s  %-5s  %-5s  %8s  %8s  %8s\n", day.Date, formatClock(day.Start), formatClock(day.End),
+			formatDuration(day.Worked), formatDuration(day.Break), rest)
+	}
+	return nil
+}
+
+// formatClock formats a time of day as HH:MM, or "-" if unknown
+func formatClock(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format("15:04")
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// WorkDay aggregates the sessions of one date for compliance checks
+type WorkDay struct {
+	Date   string
+	Start  time.Time // first session start, zero if unknown
+	End    time.Time // last session end, zero if unknown
+	Worked time.Duration
+	// Break includes recorded breaks and the gaps between sessions
+	Break time.Duration
+	// Rest is the time since the previous working day's end, zero if unknown
+	Rest time.Duration
+}
+
+// Violation is a broken compliance rule on a given day
+type Violation struct {
+	Date    string
+	Rule    string
+	Message string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s %s: %s", v.Date, v.Rule, v.Message)
+}
+
+// ComplianceRule checks a working day; previous is the preceding working day or nil
+type ComplianceRule interface {
+	Name() string
+	Check(day WorkDay, previous *WorkDay) (message string, violated bool)
+}
+
+// complianceRuleSets are the selectable values of the "compliance" config setting
+var complianceRuleSets = map[string]func(config *Config) []ComplianceRule{
+	"de": func(*Config) []ComplianceRule {
+		// German Working Hours Act (ArbZG §§3-5)
+		return []ComplianceRule{
+			minBreakRule{rules: defaultRequiredBreaks},
+			maxDailyWorkRule{max: 10 * time.Hour},
+			minRestRule{min: 11 * time.Hour},
+		}
+	},
+	"custom": func(config *Config) []ComplianceRule {
+		rules := []ComplianceRule{minBreakRule{rules: config.RequiredBreaks}}
+		if config.MaxDailyHours > 0 {
+			rules = append(rules, maxDailyWorkRule{max: hoursToDuration(config.MaxDailyHours)})
+		}
+		if config.MinRestHours > 0 {
+			rules = append(rules, minRestRule{min: hoursToDuration(config.MinRestHours)})
+		}
+		return rules
+	},
+	"none": func(*Config) []ComplianceRule { return nil },
+}
+
+// ComplianceRules returns the rules of the configured rule set
+func (c *Config) ComplianceRules() []ComplianceRule {
+	ruleSet, ok := complianceRuleSets[c.Compliance]
+	if !ok {
+		return nil
+	}
+	return ruleSet(c)
+}
+
+// CheckCompliance applies the rules to consecutive working days
+func CheckCompliance(rules []ComplianceRule, days []WorkDay) []Violation {
+	var violations []Violation
+	for i, day := range days {
+		var previous *WorkDay
+		if i > 0 {
+			previous = &days[i-1]
+		}
+		for _, rule := range rules {
+			if message, violated := rule.Check(day, previous); violated {
+				violations = append(violations, Violation{Date: day.Date, Rule: rule.Name(), Message: message})
+			}
+		}
+	}
+	return violations
+}
+
+// buildWorkDays groups sessions by date, sorted by date
+func buildWorkDays(sessions []Session) []WorkDay {
+	sorted := make([]Session, len(sessions))
+	copy(sorted, sessions)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Date != sorted[j].Date {
+			return sorted[i].Date < sorted[j].Date
+		}
+		return sorted[i].Start.Before(sorted[j].Start)
+	})
+
+	var days []WorkDay
+	var lastEnd time.Time
+	for _, session := range sorted {
+		if len(days) == 0 || days[len(days)-1].Date != session.Date {
+			days = append(days, WorkDay{Date: session.Date, Start: session.Start})
+			lastEnd = time.Time{}
+		}
+		day := &days[len(days)-1]
+
+		if work := session.Duration - session.BreakTime; work > 0 {
+			day.Worked += time.Duration(work) * time.Second
+		}
+		day.Break += time.Duration(session.BreakTime) * time.Second
+		if !lastEnd.IsZero() && !session.Start.IsZero() && session.Start.After(lastEnd) {
+			day.Break += session.Start.Sub(lastEnd)
+		}
+		if !session.End.IsZero() {
+			lastEnd = session.End
+			day.End = session.End
+		}
+	}
+
+	for i := 1; i < len(days); i++ {
+		if !days[i-1].End.IsZero() && !days[i].Start.IsZero() {
+			days[i].Rest = days[i].Start.Sub(days[i-1].End)
+		}
+	}
+	return days
+}
+
+// Compliance checks the stored sessions between from and to (zero bounds are open)
+func (s *Storage) Compliance(from, to time.Time) ([]Violation, error) {
+	config, err := s.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	days, previous, err := s.workDaysWithPrevious(from, to)
+	if err != nil {
+		return nil, err
+	}
+	if previous != nil {
+		days = append([]WorkDay{*previous}, days...)
+	}
+
+	violations := CheckCompliance(config.ComplianceRules(), days)
+	if previous == nil {
+		return violations, nil
+	}
+	filtered := violations[:0]
+	for _, violation := range violations {
+		if violation.Date != previous.Date {
+			filtered = append(filtered, violation)
+		}
+	}
+	return filtered, nil
+}
+
+// WorkDays returns the working days between from and to (zero bounds are open),
+// with the rest before the first day taken from the preceding working day
+func (s *Storage) WorkDays(from, to time.Time) ([]WorkDay, error) {
+	days, _, err := s.workDaysWithPrevious(from, to)
+	return days, err
+}
+
+func (s *Storage) workDaysWithPrevious(from, to time.Time) ([]WorkDay, *WorkDay, error) {
+	// Load everything before the range too, the preceding working day may be weeks back
+	sessions, err := s.LoadSessions(time.Time{}, to)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	days := buildWorkDays(sessions)
+	if from.IsZero() {
+		return days, nil, nil
+	}
+	first := from.Format("2006-01-02")
+	for i, day := range days {
+		if day.Date >= first {
+			if i == 0 {
+				return days, nil, nil
+			}
+			return days[i:], &days[i-1], nil
+		}
+	}
+	return nil, nil, nil
+}
+
+// minBreakRule requires breaks depending on the time worked per day
+type minBreakRule struct {
+	rules []BreakRule
+}
+
+func (minBreakRule) Name() string { return "break" }
+
+func (r minBreakRule) Check(day WorkDay, _ *WorkDay) (string, bool) {
+	config := Config{RequiredBreaks: r.rules}
+	required := config.RequiredBreak(day.Worked)
+	if day.Break >= required {
+		return "", false
+	}
+	return fmt.Sprintf("break %s is shorter than the required %s after %s of work",
+		formatDuration(day.Break), formatDuration(required), formatDuration(day.Worked)), true
+}
+
+// maxDailyWorkRule limits the working time per day
+type maxDailyWorkRule struct {
+	max time.Duration
+}
+
+func (maxDailyWorkRule) Name() string { return "max-daily" }
+
+func (r maxDailyWorkRule) Check(day WorkDay, _ *WorkDay) (string, bool) {
+	if day.Worked <= r.max {
+		return "", false
+	}
+	return fmt.Sprintf("worked %s, more than the allowed %s", formatDuration(day.Worked), formatDuration(r.max)), true
+}
+
+// minRestRule requires an uninterrupted rest between the end of one working day and the start of the next
+type minRestRule struct {
+	min time.Duration
+}
+
+func (minRestRule) Name() string { return "rest" }
+
+func (r minRestRule) Check(day WorkDay, previous *WorkDay) (string, bool) {
+	if previous == nil || day.Rest == 0 || day.Rest >= r.min {
+		return "", false
+	}
+	return fmt.Sprintf("rest of %s since %s %s is shorter than the required %s",
+		formatDuration(day.Rest), previous.Date, previous.End.Format("15:04"), formatDuration(r.min)), true
+}
+
+// complianceRuleSetNames lists the available rule sets for help and error messages
+func complianceRuleSetNames() string {
+	names := make([]string, 0, len(complianceRuleSets))
+	for name := range complianceRuleSets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGermanComplianceRules(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, 3, day, hour, minute, 0, 0, time.Local)
+	}
+	sessions := []Session{
+		// Monday: 7h work with only a 15 minute break, ends late
+		{Date: "2025-03-10", Start: at(10, 14, 0), End: at(10, 21, 15), Duration: 7*3600 + 900, BreakTime: 900},
+		// Tuesday: starts 9h after Monday's end, two sessions with a 45 minute gap, 11h of work
+		{Date: "2025-03-11", Start: at(11, 6, 15), End: at(11, 12, 15), Duration: 6 * 3600},
+		{Date: "2025-03-11", Start: at(11, 13, 0), End: at(11, 18, 0), Duration: 5 * 3600},
+		// Wednesday: legacy row without times, compliant
+		{Date: "2025-03-12", Duration: 8 * 3600, BreakTime: 1800},
+	}
+
+	config := DefaultConfig()
+	config.Compliance = "de"
+	violations := CheckCompliance(config.ComplianceRules(), buildWorkDays(sessions))
+
+	expected := []struct{ date, rule string }{
+		{"2025-03-10", "break"},
+		{"2025-03-11", "max-daily"},
+		{"2025-03-11", "rest"},
+	}
+	if len(violations) != len(expected) {
+		t.Fatalf("Expected %d violations, got %v", len(expected), violations)
+	}
+	for i, want := range expected {
+		if violations[i].Date != want.date || violations[i].Rule != want.rule {
+			t.Errorf("Violation %d: expected %s %s, got %v", i, want.date, want.rule, violations[i])
+		}
+	}
+	if !strings.Contains(violations[0].Message, "0:30:00") {
+		t.Errorf("Break violation should name the required break, got %q", violations[0].Message)
+	}
+
+	if violations := CheckCompliance(DefaultConfig().ComplianceRules(), buildWorkDays(sessions)); len(violations) != 0 {
+		t.Errorf("Compliance checks should be opt-in, got %v", violations)
+	}
+	config.Compliance = "none"
+	if violations := CheckCompliance(config.ComplianceRules(), buildWorkDays(sessions)); len(violations) != 0 {
+		t.Errorf("Rule set none should not report violations, got %v", violations)
+	}
+}
+
+func TestRunningSessionComplianceWarning(t *testing.T) {
+	timer := NewTimer()
+	timer.config = DefaultConfig()
+	timer.config.Compliance = "de"
+
+	now := time.Now()
+	timer.IsRunning = true
+	timer.SessionStart = now.Add(-6*time.Hour - 10*time.Minute)
+	timer.TodaySession = &Session{Date: now.Format("2006-01-02"), Start: timer.SessionStart}
+
+	warnings := timer.GetComplianceWarnings(now)
+	if len(warnings) != 1 || warnings[0].Rule != "break" {
+		t.Fatalf("Expected a break warning after 6h without break, got %v", warnings)
+	}
+
+	// A 30 minute break in progress satisfies the rule, even with more than 6h worked
+	timer.SessionStart = now.Add(-6*time.Hour - 40*time.Minute)
+	timer.IsOnBreak = true
+	timer.BreakStart = now.Add(-30 * time.Minute)
+	if warnings := timer.GetComplianceWarnings(now); len(warnings) != 0 {
+		t.Errorf("Expected no warnings during the break, got %v", warnings)
+	}
+}
+
+func TestRestBeforeStart(t *testing.T) {
+	timer := NewTimer()
+	timer.config = DefaultConfig()
+	now := time.Now()
+
+	// Yesterday's shift ended 9 hours ago, before the day transition moved it
+	timer.DayLastEnd = now.Add(-9 * time.Hour)
+	rest, minimum, short := timer.CheckRestBeforeStart(now)
+	if !short || minimum != 11*time.Hour {
+		t.Fatalf("Expected a short rest warning, got rest %v, minimum %v, short %v", rest, minimum, short)
+	}
+	if diff := rest - 9*time.Hour; diff < -time.Second || diff > time.Second {
+		t.Errorf("Expected 9h of rest, got %v", rest)
+	}
+
+	timer.DayLastEnd = now.Add(-12 * time.Hour)
+	if _, _, short := timer.CheckRestBeforeStart(now); short {
+		t.Error("12h of rest should not warn")
+	}
+
+	timer.config.MinRestHours = 0
+	timer.DayLastEnd = now.Add(-time.Hour)
+	if _, _, short := timer.CheckRestBeforeStart(now); short {
+		t.Error("min_rest_hours 0 should disable the warning")
+	}
+}
+
+func TestMinRestHoursZeroIsKept(t *testing.T) {
+	storage := newTestStorage(t)
+	config := DefaultConfig()
+	config.MinRestHours = 0
+	if err := storage.SaveConfig(config); err != nil {
+		t.Fatal(err)
+	}
+	// Adding a contract saves the config again
+	if err := storage.AddContract(ContractPeriod{From: "2025-01-01", WeeklyTargetHours: 30}); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := storage.LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.MinRestHours != 0 || loaded.MinRest() != 0 {
+		t.Errorf("Expected min_rest_hours 0 to survive saving, got %g", loaded.MinRestHours)
+	}
+}
+
+func TestWorkDaysRest(t *testing.T) {
+	storage := newTestStorage(t)
+	at := func(day, hour int) time.Time {
+		return time.Date(2025, 3, day, hour, 0, 0, 0, time.Local)
+	}
+	sessions := []Session{
+		{Date: "2025-03-07", Start: at(7, 9), End: at(7, 20), Duration: 11 * 3600},
+		{Date: "2025-03-10", Start: at(10, 8), End: at(10, 16), Duration: 8 * 3600},
+		{Date: "2025-03-11", Start: at(11, 7), End: at(11, 15), Duration: 8 * 3600},
+	}
+	if err := storage.appendSessionsToCSV(sessions); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first listed day still gets its rest from the preceding working day
+	days, err := storage.WorkDays(at(10, 0), at(11, 0))
+	if err != nil {
+		t.Fatalf("WorkDays failed: %v", err)
+	}
+	if len(days) != 2 {
+		t.Fatalf("Expected 2 days, got %v", days)
+	}
+	if want := at(10, 8).Sub(at(7, 20)); days[0].Rest != want {
+		t.Errorf("Expected rest %v over the weekend, got %v", want, days[0].Rest)
+	}
+	if days[1].Rest != 15*time.Hour {
+		t.Errorf("Expected 15h rest, got %v", days[1].Rest)
+	}
+}
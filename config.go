@@ -0,0 +1,225 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	defaultWeeklyTargetHours = 40
+	defaultMinRestHours      = 11
+)
+
+// Config holds user settings stored in config.json next to the data files
+type Config struct {
+	// WeeklyTargetHours is spread evenly over Monday to Friday
+	WeeklyTargetHours float64 `json:"weekly_target_hours"`
+	// WeekdayTargetHours overrides WeeklyTargetHours with hours per weekday, e.g. {"mon": 8, "thu": 4}
+	WeekdayTargetHours map[string]float64 `json:"weekday_target_hours,omitempty"`
+	// Contracts is the history of contract periods with their own schedules; the two
+	// settings above apply to dates outside every period
+	Contracts []ContractPeriod `json:"contracts,omitempty"`
+	// WeekStart is "iso" (Monday, the default), "us" (Sunday) or a weekday name such as "sat"
+	WeekStart string `json:"week_start,omitempty"`
+	// DayStartsAt is the time of day ("HH:MM") at which a working day begins, midnight if empty;
+	// sessions started earlier count towards the previous day
+	DayStartsAt string `json:"day_starts_at,omitempty"`
+	// ReportTimeZone selects the zone reports show times in: "recorded" (the zone of each
+	// session, the default), "local" (the zone of this computer) or a zone name
+	ReportTimeZone string `json:"report_time_zone,omitempty"`
+	// FlextimeStart is the first day (YYYY-MM-DD) counted in the flextime balance;
+	// the date of the first stored session is used if empty
+	FlextimeStart string `json:"flextime_start,omitempty"`
+	// RequiredBreaks lists the minimum break per day depending on the time worked
+	RequiredBreaks []BreakRule `json:"required_breaks"`
+	// Compliance selects the working-time rule set: "de", "custom" or "none" (same as empty)
+	Compliance string `json:"compliance"`
+	// MaxDailyHours and MinRestHours configure the "custom" rule set; 0 disables the check.
+	// MinRestHours is also the rest below which starting a new day shows a warning.
+	MaxDailyHours float64 `json:"max_daily_hours,omitempty"`
+	MinRestHours  float64 `json:"min_rest_hours"` // Kept when 0, which turns the warning off
+	// Holidays selects the public holiday calendar, e.g. "de" or "de-by"; holidays have no target
+	Holidays string `json:"holidays,omitempty"`
+	// VacationDays is the yearly vacation allowance in days; 0 doesn't track remaining days
+	VacationDays float64 `json:"vacation_days,omitempty"`
+	// AbsenceCredit is the share of the daily target credited per absence type, 1 if not listed
+	AbsenceCredit map[string]float64 `json:"absence_credit,omitempty"`
+	// Surcharges sorts worked time into premium buckets such as night or Sunday work for reports
+	Surcharges []SurchargeRule `json:"surcharges,omitempty"`
+	// Billing holds hourly rates and invoice settings; without it invoices can't be created
+	Billing *BillingConfig `json:"billing,omitempty"`
+}
+
+// BreakRule requires a minimum break once more than AfterHours are worked in a day
+type BreakRule struct {
+	AfterHours float64 `json:"after_hours"`
+	Minutes    float64 `json:"minutes"`
+}
+
+// defaultRequiredBreaks follows the German Working Hours Act (ArbZG §4)
+var defaultRequiredBreaks = []BreakRule{
+	{AfterHours: 6, Minutes: 30},
+	{AfterHours: 9, Minutes: 45},
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		WeeklyTargetHours: defaultWeeklyTargetHours,
+		RequiredBreaks:    defaultRequiredBreaks,
+		Compliance:        "none",
+		MinRestHours:      defaultMinRestHours,
+	}
+}
+
+// LoadConfig reads the config file, returning defaults if it doesn't exist
+func (s *Storage) LoadConfig() (*Config, error) {
+	config := DefaultConfig()
+	if s.configFile == "" {
+		return config, nil
+	}
+
+	data, err := os.ReadFile(s.configFile)
+	if os.IsNotExist(err) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", s.configFile, err)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", s.configFile, err)
+	}
+	return config, nil
+}
+
+// SaveConfig writes the config file
+func (s *Storage) SaveConfig(config *Config) error {
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+	return os.WriteFile(s.configFile, data, 0644)
+}
+
+// Validate checks the settings for values that cannot be used
+func (c *Config) Validate() error {
+	if err := validateSchedule("", c.WeeklyTargetHours, c.WeekdayTargetHours); err != nil {
+		return err
+	}
+	if err := validateContracts(c.Contracts); err != nil {
+		return err
+	}
+	for _, rule := range c.RequiredBreaks {
+		if rule.AfterHours < 0 || rule.Minutes < 0 {
+			return fmt.Errorf("required_breaks must not be negative")
+		}
+	}
+	if _, ok := complianceRuleSets[c.Compliance]; !ok && c.Compliance != "" {
+		return fmt.Errorf("unknown compliance rule set %q, use one of %s", c.Compliance, complianceRuleSetNames())
+	}
+	if c.MaxDailyHours < 0 || c.MinRestHours < 0 {
+		return fmt.Errorf("max_daily_hours and min_rest_hours must not be negative")
+	}
+	if c.Holidays != "" {
+		if _, _, err := parseHolidayCalendar(c.Holidays); err != nil {
+			return err
+		}
+	}
+	if err := validateWeekStart(c.WeekStart); err != nil {
+		return err
+	}
+	if _, err := parseDayStart(c.DayStartsAt); err != nil {
+		return err
+	}
+	if err := validateReportZone(c.ReportTimeZone); err != nil {
+		return err
+	}
+	if err := validateSurcharges(c.Surcharges); err != nil {
+		return err
+	}
+	if err := validateBilling(c.Billing); err != nil {
+		return err
+	}
+	if c.VacationDays < 0 {
+		return fmt.Errorf("vacation_days must not be negative")
+	}
+	for key, credit := range c.AbsenceCredit {
+		if _, err := parseAbsenceType(key); err != nil {
+			return fmt.Errorf("absence_credit: %w", err)
+		}
+		if credit < 0 || credit > 1 {
+			return fmt.Errorf("absence_credit: %s must be between 0 and 1", key)
+		}
+	}
+	if c.FlextimeStart != "" {
+		if _, err := time.Parse("2006-01-02", c.FlextimeStart); err != nil {
+			return fmt.Errorf("invalid flextime_start %q", c.FlextimeStart)
+		}
+	}
+	return nil
+}
+
+// DailyTarget returns the contractual working time for the given date under the
+// schedule valid on that date, 0 on public holidays
+func (c *Config) DailyTarget(date time.Time) time.Duration {
+	if _, ok := c.HolidayOn(date); ok {
+		return 0
+	}
+	weekly, weekdays := c.scheduleOn(date)
+	return scheduleTarget(weekly, weekdays, date.Weekday())
+}
+
+// WeeklyTarget returns the contractual working time for a full week under the schedule valid on date
+func (c *Config) WeeklyTarget(date time.Time) time.Duration {
+	weekly, weekdays := c.scheduleOn(date)
+	if len(weekdays) > 0 {
+		var total float64
+		for _, hours := range weekdays {
+			total += hours
+		}
+		return hoursToDuration(total)
+	}
+	return hoursToDuration(weekly)
+}
+
+// RequiredBreak returns the minimum break for a day with the given working time
+func (c *Config) RequiredBreak(worked time.Duration) time.Duration {
+	var required time.Duration
+	for _, rule := range c.RequiredBreaks {
+		if worked > hoursToDuration(rule.AfterHours) {
+			if minutes := time.Duration(rule.Minutes * float64(time.Minute)); minutes > required {
+				required = minutes
+			}
+		}
+	}
+	return required
+}
+
+// AbsenceCreditFor returns the share of the daily target credited for an absence type
+func (c *Config) AbsenceCreditFor(absenceType string) float64 {
+	if credit, ok := c.AbsenceCredit[absenceType]; ok {
+		return credit
+	}
+	return 1
+}
+
+// MinRest returns the minimum rest between two working days, 0 if not checked
+func (c *Config) MinRest() time.Duration {
+	return hoursToDuration(c.MinRestHours)
+}
+
+func hoursToDuration(hours float64) time.Duration {
+	return time.Duration(hours * float64(time.Hour)).Round(time.Second)
+}
+
+// formatSignedDuration formats a duration with a leading + or -
+func formatSignedDuration(d time.Duration) string {
+	if d < 0 {
+		return "-" + formatDuration(-d)
+	}
+	return "+" + formatDuration(d)
+}
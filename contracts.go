@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ContractPeriod is a schedule that applies from From until To (both YYYY-MM-DD, To
+// inclusive and open if empty). Dates outside every period use the top-level schedule.
+type ContractPeriod struct {
+	From string `json:"from"`
+	To   string `json:"to,omitempty"`
+	// WeeklyTargetHours and WeekdayTargetHours work like the top-level settings
+	WeeklyTargetHours  float64            `json:"weekly_target_hours"`
+	WeekdayTargetHours map[string]float64 `json:"weekday_target_hours,omitempty"`
+	Note               string             `json:"note,omitempty"`
+}
+
+// Contains reports whether the period applies on the given date
+func (p ContractPeriod) Contains(date time.Time) bool {
+	day := date.Format("2006-01-02")
+	return p.From <= day && (p.To == "" || day <= p.To)
+}
+
+// ContractOn returns the contract period valid on date, if any
+func (c *Config) ContractOn(date time.Time) (ContractPeriod, bool) {
+	for _, period := range c.Contracts {
+		if period.Contains(date) {
+			return period, true
+		}
+	}
+	return ContractPeriod{}, false
+}
+
+// scheduleOn returns the weekly and per-weekday target hours valid on date
+func (c *Config) scheduleOn(date time.Time) (float64, map[string]float64) {
+	if period, ok := c.ContractOn(date); ok {
+		return period.WeeklyTargetHours, period.WeekdayTargetHours
+	}
+	return c.WeeklyTargetHours, c.WeekdayTargetHours
+}
+
+// scheduleTarget returns the target of a weekday; per-weekday hours replace the weekly
+// hours, which are spread evenly over Monday to Friday
+func scheduleTarget(weekly float64, weekdays map[string]float64, weekday time.Weekday) time.Duration {
+	if len(weekdays) > 0 {
+		for key, hours := range weekdays {
+			if day, err := parseWeekday(key); err == nil && day == weekday {
+				return hoursToDuration(hours)
+			}
+		}
+		return 0
+	}
+	if weekday == time.Saturday || weekday == time.Sunday {
+		return 0
+	}
+	return hoursToDuration(weekly / 5)
+}
+
+// formatSchedule describes a schedule for listings, e.g. "Mon 8:00:00, Thu 4:00:00 (12:00:00/week)"
+func formatSchedule(weekly float64, weekdays map[string]float64) string {
+	if len(weekdays) == 0 {
+		return fmt.Sprintf("Mon-Fri %s (%s/week)", formatDuration(hoursToDuration(weekly/5)), formatDuration(hoursToDuration(weekly)))
+	}
+	var parts []string
+	var total time.Duration
+	for i := 1; i <= 7; i++ {
+		day := time.Weekday(i % 7)
+		if target := scheduleTarget(weekly, weekdays, day); target > 0 {
+			parts = append(parts, fmt.Sprintf("%s %s", day.String()[:3], formatDuration(target)))
+			total += target
+		}
+	}
+	return fmt.Sprintf("%s (%s/week)", strings.Join(parts, ", "), formatDuration(total))
+}
+
+// validateSchedule checks weekly and per-weekday target hours; prefix names the setting in errors
+func validateSchedule(prefix string, weekly float64, weekdays map[string]float64) error {
+	if weekly < 0 {
+		return fmt.Errorf("%sweekly_target_hours must not be negative", prefix)
+	}
+	for key, hours := range weekdays {
+		if _, err := parseWeekday(key); err != nil {
+			return fmt.Errorf("%sweekday_target_hours: %w", prefix, err)
+		}
+		if hours < 0 || hours > 24 {
+			return fmt.Errorf("%sweekday_target_hours: %s must be between 0 and 24", prefix, key)
+		}
+	}
+	return nil
+}
+
+// validateContracts checks the dates and schedules of the contract periods and that they don't overlap
+func validateContracts(periods []ContractPeriod) error {
+	sorted := make([]ContractPeriod, len(periods))
+	copy(sorted, periods)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].From < sorted[j].From })
+
+	for i, period := range sorted {
+		prefix := fmt.Sprintf("contracts (from %s): ", period.From)
+		if _, err := time.Parse("2006-01-02", period.From); err != nil {
+			return fmt.Errorf("contracts: invalid from date %q", period.From)
+		}
+		if period.To != "" {
+			if _, err := time.Parse("2006-01-02", period.To); err != nil {
+				return fmt.Errorf("%sinvalid to date %q", prefix, period.To)
+			}
+			if period.To < period.From {
+				return fmt.Errorf("%sto date %s is before the from date", prefix, period.To)
+			}
+		}
+		if err := validateSchedule(prefix, period.WeeklyTargetHours, period.WeekdayTargetHours); err != nil {
+			return err
+		}
+		if i > 0 {
+			if previous := sorted[i-1]; previous.To == "" || previous.To >= period.From {
+				return fmt.Errorf("contracts: the periods from %s and %s overlap", previous.From, period.From)
+			}
+		}
+	}
+	return nil
+}
+
+// parseWeekdayHours parses per-weekday hours such as "mon=8,tue=8,thu=4"
+func parseWeekdayHours(value string) (map[string]float64, error) {
+	hours := make(map[string]float64)
+	for _, part := range strings.Split(value, ",") {
+		key, number, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return nil, fmt.Errorf("expected WEEKDAY=HOURS, got %q", part)
+		}
+		day, err := parseWeekday(key)
+		if err != nil {
+			return nil, err
+		}
+		h, err := strconv.ParseFloat(number, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hours %q for %s", number, key)
+		}
+		hours[strings.ToLower(day.String()[:3])] = h
+	}
+	return hours, nil
+}
+
+// AddContract starts a new contract period. A period still running on its start date is
+// ended the day before; periods starting on or after it must be edited in the config file.
+func (s *Storage) AddContract(period ContractPeriod) error {
+	config, err := s.LoadConfig()
+	if err != nil {
+		return err
+	}
+	from, err := time.Parse("2006-01-02", period.From)
+	if err != nil {
+		return fmt.Errorf("invalid from date %q", period.From)
+	}
+
+	dayBefore := from.AddDate(0, 0, -1).Format("2006-01-02")
+	for i := range config.Contracts {
+		existing := &config.Contracts[i]
+		if existing.From >= period.From {
+			return fmt.Errorf("a contract period starts on %s already, edit %s to change it", existing.From, s.configFile)
+		}
+		if existing.To == "" || existing.To >= period.From {
+			existing.To = dayBefore
+		}
+	}
+	config.Contracts = append(config.Contracts, period)
+	sort.Slice(config.Contracts, func(i, j int) bool { return config.Contracts[i].From < config.Contracts[j].From })
+
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	return s.SaveConfig(config)
+}
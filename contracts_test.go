@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestContractTargets(t *testing.T) {
+	config := &Config{
+		WeeklyTargetHours: 40,
+		Contracts: []ContractPeriod{
+			{From: "2026-01-01", To: "2026-06-30", WeeklyTargetHours: 30},
+			{From: "2026-07-01", WeekdayTargetHours: map[string]float64{"mon": 8, "tue": 8, "wed": 8, "thu": 4}},
+		},
+	}
+	if err := config.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(2026, month, day, 0, 0, 0, 0, time.Local)
+	}
+	tests := []struct {
+		date   time.Time
+		target time.Duration
+	}{
+		{time.Date(2025, 12, 31, 0, 0, 0, 0, time.Local), 8 * time.Hour}, // before the first period
+		{date(6, 30), 6 * time.Hour},                                     // Tuesday, 30h spread over five days
+		{date(7, 1), 8 * time.Hour},                                      // Wednesday
+		{date(7, 2), 4 * time.Hour},                                      // Thursday
+		{date(7, 3), 0},                                                  // Friday
+	}
+	for _, tt := range tests {
+		if got := config.DailyTarget(tt.date); got != tt.target {
+			t.Errorf("%s: expected %v, got %v", tt.date.Format("2006-01-02"), tt.target, got)
+		}
+	}
+	if got := config.WeeklyTarget(date(7, 1)); got != 28*time.Hour {
+		t.Errorf("Expected a weekly target of 28h, got %v", got)
+	}
+
+	overlapping := &Config{Contracts: []ContractPeriod{
+		{From: "2026-07-01", WeeklyTargetHours: 20},
+		{From: "2026-01-01", To: "2026-07-01", WeeklyTargetHours: 30},
+	}}
+	if err := overlapping.Validate(); err == nil {
+		t.Error("Overlapping contract periods should fail validation")
+	}
+	if err := (&Config{Contracts: []ContractPeriod{{From: "2026-07-01", To: "2026-06-01"}}}).Validate(); err == nil {
+		t.Error("A period ending before it starts should fail validation")
+	}
+}
+
+func TestAddContractAndFlextime(t *testing.T) {
+	storage := newTestStorage(t)
+	if err := storage.SaveConfig(&Config{WeeklyTargetHours: 40, FlextimeStart: "2026-06-29"}); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := runContractCommand(storage, []string{"add", "-from", "2026-01-01", "-weekly", "40"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if err := runContractCommand(storage, []string{"add", "-from", "2026-07-01", "-days", "mon=8,tue=8,wed=8,thu=4", "-note", "part-time"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.AddContract(ContractPeriod{From: "2026-03-01", WeeklyTargetHours: 20}); err == nil {
+		t.Error("Adding a period before the latest one should fail")
+	}
+
+	config, err := storage.LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Contracts) != 2 || config.Contracts[0].To != "2026-06-30" || config.Contracts[1].To != "" {
+		t.Fatalf("Expected the first period to end on 2026-06-30, got %+v", config.Contracts)
+	}
+
+	out.Reset()
+	if err := runContractCommand(storage, []string{"list"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "2026-07-01  open        Mon 8:00:00, Tue 8:00:00, Wed 8:00:00, Thu 4:00:00 (28:00:00/week)  part-time") {
+		t.Errorf("Unexpected contract list:\n%s", out.String())
+	}
+
+	// Monday and Tuesday under the old contract, Wednesday to Friday under the new one
+	sessions := []Session{
+		{Date: "2026-06-29", Duration: 8 * 3600},
+		{Date: "2026-06-30", Duration: 8 * 3600},
+		{Date: "2026-07-01", Duration: 8 * 3600},
+		{Date: "2026-07-02", Duration: 5 * 3600}, // +1:00 against 4h
+		{Date: "2026-07-03", Duration: 1 * 3600}, // +1:00, no target on Friday
+	}
+	if err := storage.appendSessionsToCSV(sessions); err != nil {
+		t.Fatal(err)
+	}
+	report, err := storage.Flextime(time.Date(2026, 7, 3, 0, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Balance != 2*time.Hour {
+		t.Errorf("Expected a balance of +2:00, got %v", report.Balance)
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// DayStart returns the time of day at which a working day begins, midnight by default.
+// Work before it counts towards the previous day, so a night shift isn't split at midnight.
+func (c *Config) DayStart() time.Duration {
+	start, err := parseDayStart(c.DayStartsAt)
+	if err != nil {
+		return 0
+	}
+	return start
+}
+
+// LogicalDay returns midnight of the working day that t belongs to. The day start is
+// compared with the clock time in t's zone, so DST changes don't move it.
+func (c *Config) LogicalDay(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	hour, minute, second := t.Clock()
+	clock := time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute + time.Duration(second)*time.Second
+	if clock < c.DayStart() {
+		day = day.AddDate(0, 0, -1)
+	}
+	return day
+}
+
+// LogicalDate returns the working day that t belongs to as YYYY-MM-DD
+func (c *Config) LogicalDate(t time.Time) string {
+	return c.LogicalDay(t).Format("2006-01-02")
+}
+
+// parseDayStart parses the "day_starts_at" setting ("HH:MM"); empty means midnight
+func parseDayStart(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	start, err := parseClock(value)
+	if err != nil {
+		return 0, fmt.Errorf("day_starts_at: %w", err)
+	}
+	return start, nil
+}
+
+// Today returns the current working day according to the configured day start
+func (s *Storage) Today() (time.Time, error) {
+	config, err := s.LoadConfig()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return config.LogicalDay(time.Now()), nil
+}
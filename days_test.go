@@ -0,0 +1,72 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLogicalDay(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 3, day, hour, minute, 0, 0, time.Local)
+	}
+	tests := []struct {
+		dayStartsAt string
+		time        time.Time
+		date        string
+	}{
+		{"", at(11, 0, 30), "2026-03-11"},
+		{"04:00", at(11, 3, 59), "2026-03-10"},
+		{"04:00", at(11, 4, 0), "2026-03-11"},
+		{"04:00", at(10, 23, 0), "2026-03-10"},
+		// Before the first day of the month the previous month's last day is used
+		{"05:30", at(1, 5, 0), "2026-02-28"},
+	}
+	for _, tt := range tests {
+		config := &Config{DayStartsAt: tt.dayStartsAt}
+		if got := config.LogicalDate(tt.time); got != tt.date {
+			t.Errorf("%q %s: expected %s, got %s", tt.dayStartsAt, tt.time.Format("2006-01-02 15:04"), tt.date, got)
+		}
+	}
+
+	for _, value := range []string{"4", "25:00", "04:00:00"} {
+		if err := (&Config{DayStartsAt: value}).Validate(); err == nil {
+			t.Errorf("day_starts_at %q should fail validation", value)
+		}
+	}
+}
+
+func TestRestAcrossMidnightWithDayStart(t *testing.T) {
+	timer := NewTimer()
+	timer.config = &Config{DayStartsAt: "04:00", MinRestHours: 11}
+	timer.DayFirstStart = time.Date(2026, 3, 10, 20, 0, 0, 0, time.Local)
+	timer.DayLastEnd = time.Date(2026, 3, 11, 2, 0, 0, 0, time.Local)
+
+	// 03:00 still belongs to the working day that started at 20:00, so it isn't a new day
+	if _, _, short := timer.CheckRestBeforeStart(time.Date(2026, 3, 11, 3, 0, 0, 0, time.Local)); short {
+		t.Error("Resuming before the day start should not count as a new working day")
+	}
+	rest, _, short := timer.CheckRestBeforeStart(time.Date(2026, 3, 11, 8, 0, 0, 0, time.Local))
+	if !short || rest != 6*time.Hour {
+		t.Errorf("Expected a short rest of 6h after the day start, got %v (short %v)", rest, short)
+	}
+}
+
+func TestImportUsesDayStart(t *testing.T) {
+	storage := newTestStorage(t)
+	if err := storage.SaveConfig(&Config{WeeklyTargetHours: 40, DayStartsAt: "04:00"}); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2026, 3, 11, 1, 30, 0, 0, time.Local)
+	result := &ImportResult{Sessions: []Session{sessionFromRange(start, start.Add(time.Hour), 0, "", "")}}
+	if err := storage.ImportSessions(result, false); err != nil {
+		t.Fatal(err)
+	}
+
+	sessions, err := storage.loadSessionsFromCSV()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 1 || sessions[0].Date != "2026-03-10" {
+		t.Errorf("Expected the night session on 2026-03-10, got %+v", sessions)
+	}
+}
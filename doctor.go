@@ -0,0 +1,302 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// staleSessionAge is how long a session may run before doctor considers it forgotten
+const staleSessionAge = 24 * time.Hour
+
+// Issue is a data problem found by Diagnose
+type Issue struct {
+	File    string
+	Line    int // 0 if the problem is not tied to a line
+	Problem string
+	Fix     string // description of the automatic fix, empty if there is none
+}
+
+func (i Issue) String() string {
+	location := i.File
+	if i.Line > 0 {
+		location += ":" + strconv.Itoa(i.Line)
+	}
+	text := location + ": " + i.Problem
+	if i.Fix != "" {
+		text += " (fix: " + i.Fix + ")"
+	}
+	return text
+}
+
+// DoctorReport lists the issues of a storage and holds the repaired data
+type DoctorReport struct {
+	Issues   []Issue
+	records  [][]string // CSV records after fixes, including the header
+	timer    *Timer     // repaired timer state, nil if the JSON file needs no fix
+	closed   []Session  // stale sessions to append to the history when repairing
+	csvDirty bool
+}
+
+// Fixable returns the number of issues that Repair can fix
+func (r *DoctorReport) Fixable() int {
+	count := 0
+	for _, issue := range r.Issues {
+		if issue.Fix != "" {
+			count++
+		}
+	}
+	return count
+}
+
+// Diagnose scans the session history and the current state file for inconsistencies
+func (s *Storage) Diagnose() (*DoctorReport, error) {
+	report := &DoctorReport{}
+	if err := s.diagnoseCSV(report); err != nil {
+		return nil, err
+	}
+	if err := s.diagnoseJSON(report, time.Now()); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (s *Storage) diagnoseCSV(report *DoctorReport) error {
+	file, err := os.Open(s.csvFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", s.csvFile, err)
+	}
+	report.records = append(report.records, header)
+
+	addIssue := func(line int, problem, fix string) {
+		report.Issues = append(report.Issues, Issue{File: s.csvFile, Line: line, Problem: problem, Fix: fix})
+	}
+
+	seen := make(map[string]int)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			addIssue(parseErr.Line, parseErr.Err.Error(), "remove row")
+			report.csvDirty = true
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", s.csvFile, err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		if len(record) < 3 {
+			addIssue(line, fmt.Sprintf("row has %d fields, expected at least 3", len(record)), "remove row")
+			report.csvDirty = true
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", record[0]); err != nil {
+			addIssue(line, fmt.Sprintf("invalid date %q", record[0]), "remove row")
+			report.csvDirty = true
+			continue
+		}
+		duration, err := strconv.ParseInt(record[1], 10, 64)
+		if err != nil || duration < 0 {
+			addIssue(line, fmt.Sprintf("invalid duration %q", record[1]), "remove row")
+			report.csvDirty = true
+			continue
+		}
+		breakTime, err := strconv.ParseInt(record[2], 10, 64)
+		if err != nil || breakTime < 0 {
+			addIssue(line, fmt.Sprintf("invalid break time %q", record[2]), "set break time to 0")
+			record[2] = "0"
+			report.csvDirty = true
+		} else if breakTime > duration {
+			addIssue(line, fmt.Sprintf("break time %ds exceeds duration %ds, session is ignored in totals", breakTime, duration), "limit break time to duration")
+			record[2] = record[1]
+			report.csvDirty = true
+		}
+
+		if len(record) >= 9 && record[8] != "" {
+			if _, ok := loadZone(record[8]); !ok {
+				addIssue(line, fmt.Sprintf("unknown time zone %q", record[8]), "show times in UTC")
+				record[8] = ""
+				report.csvDirty = true
+			}
+		}
+
+		key := strings.Join(record, ",")
+		if first, ok := seen[key]; ok {
+			if len(record) < 4 || record[3] == "" {
+				// Rows without a start time may be separate blocks of the same length
+				addIssue(line, fmt.Sprintf("same date and times as line %d, but without a start time it may be a separate session", first), "")
+			} else {
+				addIssue(line, fmt.Sprintf("duplicate of line %d", first), "remove row")
+				report.csvDirty = true
+				continue
+			}
+		} else {
+			seen[key] = line
+		}
+
+		report.records = append(report.records, record)
+	}
+	return nil
+}
+
+func (s *Storage) diagnoseJSON(report *DoctorReport, now time.Time) error {
+	data, err := os.ReadFile(s.jsonFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var timer Timer
+	if err := json.Unmarshal(data, &timer); err != nil {
+		report.Issues = append(report.Issues, Issue{File: s.jsonFile, Problem: fmt.Sprintf("cannot be parsed: %v", err)})
+		return nil
+	}
+
+	addIssue := func(key, problem, fix string) {
+		report.Issues = append(report.Issues, Issue{File: s.jsonFile, Line: jsonKeyLine(data, key), Problem: problem, Fix: fix})
+		report.timer = &timer
+	}
+
+	if timer.IsRunning && timer.TodaySession == nil {
+		addIssue("is_running", "session is running but has no session data", "discard running session")
+		timer.Reset()
+	} else if timer.IsRunning && now.Sub(timer.SessionStart) > staleSessionAge {
+		problem := fmt.Sprintf("session has been running since %s", timer.SessionStart.Format("2006-01-02 15:04"))
+		config, err := s.LoadConfig()
+		if err != nil {
+			config = DefaultConfig()
+		}
+		if session, ok := closeStaleSession(&timer, config); ok {
+			addIssue("is_running", problem, fmt.Sprintf("end session at %s", session.End.Format("2006-01-02 15:04")))
+			report.closed = append(report.closed, session)
+		} else {
+			addIssue("is_running", problem, "discard running session")
+		}
+		timer.Reset()
+	}
+	if timer.IsOnBreak && !timer.IsRunning {
+		addIssue("is_on_break", "on break without a running session", "end break")
+		timer.IsOnBreak = false
+	}
+	if timer.DailyTotal < 0 {
+		addIssue("daily_total", "negative daily total", "set daily total to 0")
+		timer.DailyTotal = 0
+	}
+	return nil
+}
+
+// closeStaleSession ends a forgotten session at its last known activity: the start of
+// an ongoing break, or else the daily target after the start, but not before the last
+// break ended. It reports false if that leaves no time to record.
+func closeStaleSession(timer *Timer, config *Config) (Session, bool) {
+	session := *timer.TodaySession
+	breakTime := time.Duration(session.BreakTime) * time.Second
+	var end time.Time
+	if timer.IsOnBreak {
+		end = timer.BreakStart
+	} else {
+		end = timer.SessionStart.Add(config.DailyTarget(timer.SessionStart) + breakTime)
+		if len(session.Breaks) > 0 {
+			if last := session.Breaks[len(session.Breaks)-1].End; last.After(end) {
+				end = last
+			}
+		}
+	}
+	if !end.After(timer.SessionStart) {
+		return session, false
+	}
+
+	session.Start = timer.SessionStart
+	session.End = end
+	session.Duration = int64(end.Sub(timer.SessionStart).Seconds())
+	session.BreakTime = min(session.BreakTime, session.Duration)
+	return session, true
+}
+
+// jsonKeyLine returns the 1-based line of the first occurrence of a JSON key, or 0
+func jsonKeyLine(data []byte, key string) int {
+	index := bytes.Index(data, []byte(`"`+key+`"`))
+	if index < 0 {
+		return 0
+	}
+	return bytes.Count(data[:index], []byte("\n")) + 1
+}
+
+// Repair applies the fixes of a report, keeping a .bak copy of every file it rewrites
+func (s *Storage) Repair(report *DoctorReport) error {
+	if report.csvDirty {
+		if err := backupFile(s.csvFile); err != nil {
+			return err
+		}
+		file, err := os.Create(s.csvFile)
+		if err != nil {
+			return err
+		}
+		writer := csv.NewWriter(file)
+		writer.WriteAll(report.records)
+		if err := writer.Error(); err != nil {
+			file.Close()
+			return err
+		}
+		if err := file.Close(); err != nil {
+			return err
+		}
+	}
+
+	if len(report.closed) > 0 {
+		if err := s.appendSessionsToCSV(report.closed); err != nil {
+			return err
+		}
+	}
+
+	if report.timer != nil {
+		if err := backupFile(s.jsonFile); err != nil {
+			return err
+		}
+		data, err := json.MarshalIndent(report.timer, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal timer: %w", err)
+		}
+		if err := os.WriteFile(s.jsonFile, data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backupFile copies path to path.bak
+func backupFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path+".bak", data, 0644)
+}
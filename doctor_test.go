@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDoctorFindsAndFixesProblems(t *testing.T) {
+	storage := newTestStorage(t)
+	csv := strings.Join([]string{
+		"date,duration_s,break_time_s",
+		"2025-03-10,3600,600",
+		"2025-03-10,3600,600",
+		"2025-13-40,3600,0",
+		"2025-03-11,1800,2400",
+		"2025-03-12,3600,0",
+		"2025-03-13,3600,0,2025-03-13T08:00:00Z,2025-03-13T09:00:00Z,,,,",
+		"2025-03-13,3600,0,2025-03-13T08:00:00Z,2025-03-13T09:00:00Z,,,,",
+	}, "\n") + "\n"
+	if err := os.WriteFile(storage.csvFile, []byte(csv), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A Monday session with a half-hour break, forgotten for days
+	start := time.Date(2025, 3, 17, 8, 0, 0, 0, time.Local)
+	stale := NewTimer()
+	stale.Start()
+	stale.SessionStart = start
+	stale.TodaySession.Date = "2025-03-17"
+	stale.TodaySession.BreakTime = 1800
+	stale.TodaySession.Breaks = []Break{{Start: start.Add(2 * time.Hour), End: start.Add(150 * time.Minute)}}
+	data, _ := json.MarshalIndent(stale, "", "  ")
+	if err := os.WriteFile(storage.jsonFile, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := storage.Diagnose()
+	if err != nil {
+		t.Fatalf("Diagnose failed: %v", err)
+	}
+
+	if !strings.Contains(report.Issues[len(report.Issues)-1].Fix, "end session at 2025-03-17 16:30") {
+		t.Errorf("Expected the stale session to end after the daily target and its break, got %v", report.Issues)
+	}
+
+	expected := map[int]string{3: "may be a separate session", 4: "invalid date", 5: "exceeds duration", 8: "duplicate of line 7"}
+	for line, problem := range expected {
+		found := false
+		for _, issue := range report.Issues {
+			if issue.File == storage.csvFile && issue.Line == line && strings.Contains(issue.Problem, problem) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Expected issue %q on line %d, got %v", problem, line, report.Issues)
+		}
+	}
+	// Rows without a start time that look alike are only reported
+	if len(report.Issues) != 5 || report.Fixable() != 4 {
+		t.Fatalf("Expected 4 fixable issues and a legacy look-alike, got %v", report.Issues)
+	}
+
+	if err := storage.Repair(report); err != nil {
+		t.Fatalf("Repair failed: %v", err)
+	}
+	if _, err := os.Stat(storage.csvFile + ".bak"); err != nil {
+		t.Error("Repair should keep a backup of the sessions file")
+	}
+
+	sessions, err := storage.loadSessionsFromCSV()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 6 {
+		t.Fatalf("Expected 5 sessions and the closed one after repair, got %d", len(sessions))
+	}
+	if sessions[2].BreakTime != sessions[2].Duration {
+		t.Errorf("Break time should be limited to duration, got %+v", sessions[2])
+	}
+	if closed := sessions[5]; closed.Date != "2025-03-17" || closed.Duration != 8*3600+1800 || closed.BreakTime != 1800 || len(closed.Breaks) != 1 {
+		t.Errorf("Expected the stale session to be recorded, got %+v", closed)
+	}
+
+	timer, err := storage.LoadTimer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if timer.IsRunning {
+		t.Error("Stale running session should be ended")
+	}
+
+	report, err = storage.Diagnose()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Fixable() != 0 {
+		t.Errorf("Expected no fixable issues after repair, got %v", report.Issues)
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"time"
+)
+
+// WeekBalance compares worked and target time of one week
+type WeekBalance struct {
+	Year   int
+	Week   int
+	Worked time.Duration
+	Target time.Duration
+	// Booked is the sum of the week's bookings (time in lieu, payouts, corrections)
+	Booked time.Duration
+}
+
+// Delta returns the change of the balance in the week, overtime (positive) or undertime (negative) plus bookings
+func (w WeekBalance) Delta() time.Duration {
+	return w.Worked - w.Target + w.Booked
+}
+
+// FlextimeReport is the overtime balance from a start date up to and including a end date
+type FlextimeReport struct {
+	Start   time.Time
+	End     time.Time
+	Weeks   []WeekBalance
+	Balance time.Duration
+}
+
+// dailyWork sums the worked time (duration minus break) per session date
+func dailyWork(sessions []Session) map[string]time.Duration {
+	worked := make(map[string]time.Duration)
+	for _, session := range sessions {
+		if work := session.Duration - session.BreakTime; work > 0 {
+			worked[session.Date] += time.Duration(work) * time.Second
+		}
+	}
+	return worked
+}
+
+// counts reports whether a booking falls within the balance period
+func (r *FlextimeReport) counts(booking Booking) bool {
+	if r.Start.IsZero() {
+		return false
+	}
+	return booking.Date >= r.Start.Format("2006-01-02") && booking.Date <= r.End.Format("2006-01-02")
+}
+
+// computeFlextime balances the worked time per day against the configured targets from start to end,
+// with the targets reduced by absences and the bookings within the period added. The target of
+// today, the day still being worked, only counts as far as it is worked: today adds overtime,
+// but undertime only once the day is over.
+func computeFlextime(config *Config, absences Absences, bookings []Booking, worked map[string]time.Duration, start, end, today time.Time) *FlextimeReport {
+	report := &FlextimeReport{Start: start, End: end}
+	if start.IsZero() || end.Before(start) {
+		return report
+	}
+
+	booked := make(map[string]time.Duration)
+	for _, booking := range bookings {
+		booked[booking.Date] += booking.Amount
+	}
+
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		year, week := config.WeekOf(day)
+		if n := len(report.Weeks); n == 0 || report.Weeks[n-1].Year != year || report.Weeks[n-1].Week != week {
+			report.Weeks = append(report.Weeks, WeekBalance{Year: year, Week: week})
+		}
+		current := &report.Weeks[len(report.Weeks)-1]
+		date := day.Format("2006-01-02")
+		target := absences.Target(config, day)
+		if date == today.Format("2006-01-02") {
+			target = min(target, worked[date])
+		}
+		current.Worked += worked[date]
+		current.Target += target
+		current.Booked += booked[date]
+	}
+
+	for _, week := range report.Weeks {
+		report.Balance += week.Delta()
+	}
+	return report
+}
+
+// flextimeStart returns the configured start date, or the first date with worked time
+func flextimeStart(config *Config, worked map[string]time.Duration) time.Time {
+	if config.FlextimeStart != "" {
+		start, err := time.ParseInLocation("2006-01-02", config.FlextimeStart, time.Local)
+		if err == nil {
+			return start
+		}
+	}
+
+	first := ""
+	for date := range worked {
+		if first == "" || date < first {
+			first = date
+		}
+	}
+	start, err := time.ParseInLocation("2006-01-02", first, time.Local)
+	if err != nil {
+		return time.Time{}
+	}
+	return start
+}
+
+// Flextime computes the overtime balance of the stored sessions up to and including end;
+// if end is today, its open target isn't counted yet (see computeFlextime)
+func (s *Storage) Flextime(end time.Time) (*FlextimeReport, error) {
+	config, err := s.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	today, err := s.Today()
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := s.loadSessionsFromCSV()
+	if err != nil {
+		return nil, err
+	}
+	absences, err := s.LoadAbsences()
+	if err != nil {
+		return nil, err
+	}
+	bookings, err := s.allBookings(config, absences)
+	if err != nil {
+		return nil, err
+	}
+
+	worked := dailyWork(sessions)
+	end = time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.Local)
+	return computeFlextime(config, absences, bookings, worked, flextimeStart(config, worked), end, today), nil
+}
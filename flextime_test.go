@@ -0,0 +1,130 @@
+package main
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestConfigDailyTarget(t *testing.T) {
+	monday := time.Date(2025, 3, 10, 0, 0, 0, 0, time.Local)
+	saturday := time.Date(2025, 3, 15, 0, 0, 0, 0, time.Local)
+
+	config := DefaultConfig()
+	if got := config.DailyTarget(monday); got != 8*time.Hour {
+		t.Errorf("Default Monday target should be 8h, got %v", got)
+	}
+	if got := config.DailyTarget(saturday); got != 0 {
+		t.Errorf("Default Saturday target should be 0, got %v", got)
+	}
+
+	config.WeekdayTargetHours = map[string]float64{"mon": 8, "tue": 8, "wed": 8, "thu": 4}
+	if got := config.DailyTarget(monday.AddDate(0, 0, 3)); got != 4*time.Hour {
+		t.Errorf("Thursday target should be 4h, got %v", got)
+	}
+	if got := config.DailyTarget(monday.AddDate(0, 0, 4)); got != 0 {
+		t.Errorf("Friday target should be 0, got %v", got)
+	}
+	if got := config.WeeklyTarget(monday); got != 28*time.Hour {
+		t.Errorf("Weekly target should be 28h, got %v", got)
+	}
+}
+
+func TestFlextimeBalance(t *testing.T) {
+	storage := newTestStorage(t)
+	config := &Config{WeeklyTargetHours: 40, FlextimeStart: "2025-03-07"} // Friday
+	if err := storage.SaveConfig(config); err != nil {
+		t.Fatal(err)
+	}
+	sessions := []Session{
+		{Date: "2025-03-07", Duration: 9 * 3600, BreakTime: 1800}, // +0:30
+		{Date: "2025-03-10", Duration: 10 * 3600},                 // +2:00
+		{Date: "2025-03-11", Duration: 6 * 3600},                  // -2:00
+		{Date: "2025-03-06", Duration: 8 * 3600},                  // before start, ignored
+	}
+	if err := storage.appendSessionsToCSV(sessions); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := storage.Flextime(time.Date(2025, 3, 12, 15, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatalf("Flextime failed: %v", err)
+	}
+	if len(report.Weeks) != 2 {
+		t.Fatalf("Expected 2 weeks, got %d", len(report.Weeks))
+	}
+	if report.Weeks[0].Week != 10 || report.Weeks[0].Delta() != 30*time.Minute {
+		t.Errorf("Unexpected first week: %+v", report.Weeks[0])
+	}
+	// Wednesday is over without a session, so its full target counts
+	if report.Weeks[1].Delta() != -8*time.Hour {
+		t.Errorf("Unexpected second week: %+v delta %v", report.Weeks[1], report.Weeks[1].Delta())
+	}
+	if report.Balance != -7*time.Hour-30*time.Minute {
+		t.Errorf("Expected balance -7:30:00, got %v", report.Balance)
+	}
+}
+
+func TestInvalidConfig(t *testing.T) {
+	storage := newTestStorage(t)
+	if err := os.WriteFile(storage.configFile, []byte(`{"weekday_target_hours": {"someday": 8}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := storage.LoadConfig(); err == nil {
+		t.Error("Loading a config with an unknown weekday should fail")
+	}
+}
+
+func TestFlextimeBalanceDuringTheDay(t *testing.T) {
+	storage := newTestStorage(t)
+	today, err := storage.Today()
+	if err != nil {
+		t.Fatal(err)
+	}
+	yesterday := today.AddDate(0, 0, -1).Format("2006-01-02")
+	everyDay := map[string]float64{"mon": 8, "tue": 8, "wed": 8, "thu": 8, "fri": 8, "sat": 8, "sun": 8}
+	if err := storage.SaveConfig(&Config{WeekdayTargetHours: everyDay, FlextimeStart: yesterday}); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.appendSessionsToCSV([]Session{{Date: yesterday, Duration: 9 * 3600}}); err != nil {
+		t.Fatal(err)
+	}
+
+	timer := NewTimer()
+	timer.SetStorage(storage)
+	// Nothing worked yet today: only yesterday's overtime, not -8h for today's target
+	if balance := timer.GetFlextimeBalance(); balance != time.Hour {
+		t.Errorf("Expected +1:00 in the morning, got %v", balance)
+	}
+	report, err := storage.Flextime(today)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Balance != time.Hour {
+		t.Errorf("Expected the reports to agree with +1:00, got %v", report.Balance)
+	}
+
+	if err := storage.appendSessionsToCSV([]Session{{Date: today.Format("2006-01-02"), Duration: 3 * 3600}}); err != nil {
+		t.Fatal(err)
+	}
+	timer.updateTotals()
+	if balance := timer.GetFlextimeBalance(); balance != time.Hour {
+		t.Errorf("Expected +1:00 while today's target isn't reached, got %v", balance)
+	}
+	if err := storage.appendSessionsToCSV([]Session{{Date: today.Format("2006-01-02"), Duration: 6 * 3600}}); err != nil {
+		t.Fatal(err)
+	}
+	timer.updateTotals()
+	report, _ = storage.Flextime(today)
+	if balance := timer.GetFlextimeBalance(); balance != 2*time.Hour || report.Balance != 2*time.Hour {
+		t.Errorf("Expected +2:00 with 9h worked today, got %v (timer) and %v (report)", balance, report.Balance)
+	}
+
+	// 9h done and 1h in the running session
+	timer.IsRunning = true
+	timer.SessionStart = time.Now().Add(-time.Hour)
+	timer.TodaySession = &Session{Date: today.Format("2006-01-02")}
+	if balance := timer.GetFlextimeBalance(); balance < 3*time.Hour || balance > 3*time.Hour+time.Minute {
+		t.Errorf("Expected about +3:00 with today's overtime, got %v", balance)
+	}
+}
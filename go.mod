@@ -4,7 +4,10 @@ go 1.23.0
 
 toolchain go1.24.3
 
-require fyne.io/fyne/v2 v2.6.1
+require (
+	fyne.io/fyne/v2 v2.6.1
+	golang.org/x/term v0.32.0
+)
 
 require (
 	fyne.io/systray v1.11.0 // indirect
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	heatmapWindowTitle  = "Heatmap"
+	heatmapWindowWidth  = 900
+	heatmapWindowHeight = 360
+	heatmapCellSize     = 11
+	heatmapHint         = "Hover over or click a day to see its sessions"
+)
+
+// heatmapColors are the cell colours from no work to the target exceeded (GitHub palette)
+var heatmapColors = []color.Color{
+	color.NRGBA{0xeb, 0xed, 0xf0, 0xff},
+	color.NRGBA{0x9b, 0xe9, 0xa8, 0xff},
+	color.NRGBA{0x40, 0xc4, 0x63, 0xff},
+	color.NRGBA{0x30, 0xa1, 0x4e, 0xff},
+	color.NRGBA{0x21, 0x6e, 0x39, 0xff},
+}
+
+// Outline colours of public holidays and absences
+var (
+	heatmapHolidayColor = color.NRGBA{0xd7, 0x3a, 0x49, 0xff}
+	heatmapAbsenceColor = color.NRGBA{0x40, 0x78, 0xc0, 0xff}
+)
+
+// heatmapLevel maps the worked time relative to the day's target to an index into heatmapColors
+func heatmapLevel(worked, target time.Duration) int {
+	switch {
+	case worked <= 0:
+		return 0
+	case target <= 0:
+		return len(heatmapColors) - 1 // Any work on a day without target is overtime
+	}
+	ratio := float64(worked) / float64(target)
+	switch {
+	case ratio < 0.5:
+		return 1
+	case ratio < 0.9:
+		return 2
+	case ratio <= 1.1:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// HeatmapWindow shows a year of worked hours as one coloured cell per day
+type HeatmapWindow struct {
+	window       fyne.Window
+	storage      *Storage
+	year         int
+	yearLabel    *widget.Label
+	grid         *fyne.Container
+	detailsLabel *widget.Label
+}
+
+func NewHeatmapWindow(app fyne.App, storage *Storage) *HeatmapWindow {
+	hw := &HeatmapWindow{
+		window:       app.NewWindow(heatmapWindowTitle),
+		storage:      storage,
+		year:         time.Now().Year(),
+		yearLabel:    widget.NewLabelWithStyle("", fyne.TextAlignCenter, fyne.TextStyle{Bold: true}),
+		grid:         container.NewHBox(),
+		detailsLabel: widget.NewLabel(heatmapHint),
+	}
+
+	navigation := container.NewHBox(
+		widget.NewButton("<", func() { hw.showYear(hw.year - 1) }),
+		hw.yearLabel,
+		widget.NewButton(">", func() { hw.showYear(hw.year + 1) }),
+	)
+	hw.window.SetContent(container.NewBorder(
+		container.NewCenter(navigation),
+		nil, nil, nil,
+		container.NewVBox(container.NewHScroll(hw.grid), hw.detailsLabel),
+	))
+	hw.window.Resize(fyne.NewSize(heatmapWindowWidth, heatmapWindowHeight))
+	hw.showYear(hw.year)
+
+	return hw
+}
+
+// showYear fills the grid with one column per week, the configured first weekday in the first row
+func (hw *HeatmapWindow) showYear(year int) {
+	hw.year = year
+	hw.yearLabel.SetText(fmt.Sprint(year))
+	hw.detailsLabel.SetText(heatmapHint)
+
+	config, err := hw.storage.LoadConfig()
+	if err != nil {
+		hw.detailsLabel.SetText("Error: " + err.Error())
+		return
+	}
+	first := time.Date(year, time.January, 1, 0, 0, 0, 0, time.Local)
+	days, err := hw.storage.DailySummaries(first, first.AddDate(1, 0, -1))
+	if err != nil {
+		hw.detailsLabel.SetText("Error: " + err.Error())
+		return
+	}
+
+	firstWeekday := config.FirstWeekday()
+	columns := []fyne.CanvasObject{hw.weekdayLabels(firstWeekday)}
+	var column *fyne.Container
+	for i, day := range days {
+		row := (int(day.Date.Weekday()) - int(firstWeekday) + 7) % 7
+		if column == nil || row == 0 {
+			column = container.NewVBox()
+			columns = append(columns, column)
+			for j := 0; i == 0 && j < row; j++ {
+				column.Add(newHeatmapCell(color.Transparent, nil))
+			}
+		}
+		day := day
+		cell := newHeatmapCell(heatmapColors[heatmapLevel(day.Worked, day.Target)], func() { hw.showDay(day) })
+		if day.Holiday != "" {
+			cell.rect.StrokeColor = heatmapHolidayColor
+			cell.rect.StrokeWidth = 1.5
+		} else if day.Absence != nil {
+			cell.rect.StrokeColor = heatmapAbsenceColor
+			cell.rect.StrokeWidth = 1.5
+		}
+		column.Add(cell)
+	}
+	hw.grid.Objects = columns
+	hw.grid.Refresh()
+}
+
+// weekdayLabels names every other row, starting with the first weekday
+func (hw *HeatmapWindow) weekdayLabels(first time.Weekday) fyne.CanvasObject {
+	labels := container.NewVBox()
+	for row := 0; row < 7; row++ {
+		name := ""
+		if row%2 == 0 && row < 6 {
+			name = time.Weekday((int(first) + row) % 7).String()[:3]
+		}
+		text := canvas.NewText(name, color.Gray{Y: 0x80})
+		text.TextSize = heatmapCellSize
+		// Fix the row height to the cell size, the text alone would be taller
+		labels.Add(container.NewGridWrap(fyne.NewSize(3*heatmapCellSize, heatmapCellSize), text))
+	}
+	return labels
+}
+
+func (hw *HeatmapWindow) showDay(day DaySummary) {
+	lines := []string{fmt.Sprintf("%s: worked %s of %s", day.Date.Format("Mon 2006-01-02"),
+		formatDuration(day.Worked), formatDuration(day.Target))}
+	if note := day.Note(); note != "" {
+		lines[0] += " - " + note
+	}
+	for _, session := range day.Sessions {
+		lines = append(lines, formatSessionLine(session))
+	}
+	hw.detailsLabel.SetText(strings.Join(lines, "\n"))
+}
+
+func (hw *HeatmapWindow) Show() {
+	hw.window.Show()
+}
+
+// heatmapCell is a coloured square that reports hover and tap
+type heatmapCell struct {
+	widget.BaseWidget
+	rect     *canvas.Rectangle
+	onSelect func()
+}
+
+var _ desktop.Hoverable = (*heatmapCell)(nil)
+
+func newHeatmapCell(fill color.Color, onSelect func()) *heatmapCell {
+	rect := canvas.NewRectangle(fill)
+	rect.SetMinSize(fyne.NewSize(heatmapCellSize, heatmapCellSize))
+	rect.CornerRadius = 2
+	cell := &heatmapCell{rect: rect, onSelect: onSelect}
+	cell.ExtendBaseWidget(cell)
+	return cell
+}
+
+func (c *heatmapCell) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(c.rect)
+}
+
+func (c *heatmapCell) Tapped(*fyne.PointEvent) { c.selectDay() }
+
+func (c *heatmapCell) MouseIn(*desktop.MouseEvent) { c.selectDay() }
+
+func (c *heatmapCell) MouseMoved(*desktop.MouseEvent) {}
+
+func (c *heatmapCell) MouseOut() {}
+
+func (c *heatmapCell) selectDay() {
+	if c.onSelect != nil {
+		c.onSelect()
+	}
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHeatmapLevel(t *testing.T) {
+	tests := []struct {
+		worked, target time.Duration
+		want           int
+	}{
+		{0, 8 * time.Hour, 0},
+		{2 * time.Hour, 8 * time.Hour, 1},
+		{6 * time.Hour, 8 * time.Hour, 2},
+		{8 * time.Hour, 8 * time.Hour, 3},
+		{10 * time.Hour, 8 * time.Hour, 4},
+		{time.Hour, 0, 4},
+		{0, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := heatmapLevel(tt.worked, tt.target); got != tt.want {
+			t.Errorf("heatmapLevel(%v, %v) = %d, want %d", tt.worked, tt.target, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	historyWindowTitle  = "History"
+	historyWindowWidth  = 600
+	historyWindowHeight = 400
+	historyMaxRows      = 1000
+	historyPlaceholder  = "e.g. work > 6h and break = 0 and weekday = fri"
+)
+
+// HistoryWindow lists stored sessions filtered by a query
+type HistoryWindow struct {
+	window       fyne.Window
+	storage      *Storage
+	queryEntry   *widget.Entry
+	list         *widget.List
+	summaryLabel *widget.Label
+	sessions     []Session
+	config       *Config
+}
+
+func NewHistoryWindow(app fyne.App, storage *Storage) *HistoryWindow {
+	hw := &HistoryWindow{
+		window:  app.NewWindow(historyWindowTitle),
+		storage: storage,
+	}
+	config, err := storage.LoadConfig()
+	if err != nil {
+		config = DefaultConfig()
+	}
+	hw.config = config
+
+	hw.queryEntry = widget.NewEntry()
+	hw.queryEntry.SetPlaceHolder(historyPlaceholder)
+	hw.queryEntry.OnSubmitted = func(string) { hw.runQuery() }
+
+	hw.list = widget.NewList(
+		func() int { return len(hw.sessions) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			item.(*widget.Label).SetText(hw.formatLine(hw.sessions[id]))
+		},
+	)
+	hw.summaryLabel = widget.NewLabel("")
+
+	hw.window.SetContent(container.NewBorder(
+		container.NewBorder(nil, nil, nil, widget.NewButton("Search", hw.runQuery), hw.queryEntry),
+		hw.summaryLabel,
+		nil, nil,
+		hw.list,
+	))
+	hw.window.Resize(fyne.NewSize(historyWindowWidth, historyWindowHeight))
+	hw.runQuery()
+
+	return hw
+}
+
+func (hw *HistoryWindow) runQuery() {
+	query, err := ParseQuery(hw.queryEntry.Text)
+	if err != nil {
+		hw.summaryLabel.SetText("Invalid query: " + err.Error())
+		return
+	}
+
+	var sessions []Session
+	var count int
+	var work time.Duration
+	err = hw.storage.QuerySessions(query, func(session Session) bool {
+		count++
+		work += time.Duration(session.Duration-session.BreakTime) * time.Second
+		if len(sessions) < historyMaxRows {
+			sessions = append(sessions, session)
+		}
+		return true
+	})
+	if err != nil {
+		hw.summaryLabel.SetText("Error: " + err.Error())
+		return
+	}
+
+	hw.sessions = sessions
+	hw.list.Refresh()
+	summary := fmt.Sprintf("%d sessions, work %s", count, formatDuration(work))
+	if count > len(sessions) {
+		summary += fmt.Sprintf(" (showing first %d)", len(sessions))
+	}
+	hw.summaryLabel.SetText(summary)
+}
+
+// formatLine adds the holiday name to sessions on public holidays
+func (hw *HistoryWindow) formatLine(session Session) string {
+	line := formatSessionLine(session)
+	if date, err := time.ParseInLocation("2006-01-02", session.Date, time.Local); err == nil {
+		if holiday, ok := hw.config.HolidayOn(date); ok {
+			line += "  [" + holiday + "]"
+		}
+	}
+	return line
+}
+
+func (hw *HistoryWindow) Show() {
+	hw.window.Show()
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Holiday is a public holiday on a given date
+type Holiday struct {
+	Date time.Time
+	Name string
+}
+
+// holidayRule computes one holiday per year
+type holidayRule struct {
+	name string
+	date func(year int) time.Time
+	// regions lists the regions observing the holiday; empty means nationwide
+	regions []string
+	// from and to limit the years the holiday exists; 0 leaves that side open
+	from, to int
+}
+
+// holidayCountry is a selectable holiday calendar with its regions
+type holidayCountry struct {
+	regions []string
+	rules   []holidayRule
+}
+
+// holidayCountries are the calendars selectable with the "holidays" config setting,
+// either as country ("de") or country and region ("de-by")
+var holidayCountries = map[string]holidayCountry{
+	"de": {
+		regions: []string{"bb", "be", "bw", "by", "hb", "he", "hh", "mv", "ni", "nw", "rp", "sh", "sl", "sn", "st", "th"},
+		rules: []holidayRule{
+			{name: "New Year's Day", date: fixedDate(time.January, 1)},
+			{name: "Epiphany", date: fixedDate(time.January, 6), regions: []string{"bw", "by", "st"}},
+			{name: "International Women's Day", date: fixedDate(time.March, 8), regions: []string{"be"}, from: 2019},
+			{name: "International Women's Day", date: fixedDate(time.March, 8), regions: []string{"mv"}, from: 2023},
+			{name: "Good Friday", date: easterOffset(-2)},
+			{name: "Easter Sunday", date: easterOffset(0), regions: []string{"bb"}},
+			{name: "Easter Monday", date: easterOffset(1)},
+			{name: "Labour Day", date: fixedDate(time.May, 1)},
+			{name: "Ascension Day", date: easterOffset(39)},
+			{name: "Whit Sunday", date: easterOffset(49), regions: []string{"bb"}},
+			{name: "Whit Monday", date: easterOffset(50)},
+			{name: "Corpus Christi", date: easterOffset(60), regions: []string{"bw", "by", "he", "nw", "rp", "sl"}},
+			{name: "Assumption Day", date: fixedDate(time.August, 15), regions: []string{"sl"}},
+			{name: "World Children's Day", date: fixedDate(time.September, 20), regions: []string{"th"}, from: 2019},
+			{name: "German Unity Day", date: fixedDate(time.October, 3)},
+			{name: "Reformation Day", date: fixedDate(time.October, 31), regions: []string{"bb", "mv", "sn", "st", "th"}, to: 2016},
+			// Observed nationwide for the 500th anniversary of the Reformation
+			{name: "Reformation Day", date: fixedDate(time.October, 31), from: 2017, to: 2017},
+			{name: "Reformation Day", date: fixedDate(time.October, 31), regions: []string{"bb", "hb", "hh", "mv", "ni", "sh", "sn", "st", "th"}, from: 2018},
+			{name: "All Saints' Day", date: fixedDate(time.November, 1), regions: []string{"bw", "by", "nw", "rp", "sl"}},
+			{name: "Day of Repentance and Prayer", date: repentanceDay, regions: []string{"sn"}},
+			{name: "Christmas Day", date: fixedDate(time.December, 25)},
+			{name: "St. Stephen's Day", date: fixedDate(time.December, 26)},
+		},
+	},
+}
+
+func fixedDate(month time.Month, day int) func(year int) time.Time {
+	return func(year int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+	}
+}
+
+func easterOffset(days int) func(year int) time.Time {
+	return func(year int) time.Time {
+		return easterSunday(year).AddDate(0, 0, days)
+	}
+}
+
+// easterSunday computes the date of Easter Sunday in the Gregorian calendar
+// (anonymous Gregorian algorithm by Meeus, Jones and Butcher)
+func easterSunday(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.Local)
+}
+
+// repentanceDay is the Wednesday before November 23
+func repentanceDay(year int) time.Time {
+	date := time.Date(year, time.November, 22, 0, 0, 0, 0, time.Local)
+	for date.Weekday() != time.Wednesday {
+		date = date.AddDate(0, 0, -1)
+	}
+	return date
+}
+
+// parseHolidayCalendar splits "de-by" into its country and region and checks both exist
+func parseHolidayCalendar(calendar string) (holidayCountry, string, error) {
+	code, region, _ := strings.Cut(strings.ToLower(calendar), "-")
+	country, ok := holidayCountries[code]
+	if !ok {
+		return country, "", fmt.Errorf("unknown holiday calendar %q, use one of %s", calendar, holidayCalendarNames())
+	}
+	if region != "" && !slices.Contains(country.regions, region) {
+		return country, "", fmt.Errorf("unknown region %q for holiday calendar %q, use one of %s",
+			region, code, strings.Join(country.regions, ", "))
+	}
+	return country, region, nil
+}
+
+// Holidays returns the holidays of the calendar in the given year, sorted by date.
+// Without a region only the nationwide holidays are included.
+func Holidays(calendar string, year int) ([]Holiday, error) {
+	country, region, err := parseHolidayCalendar(calendar)
+	if err != nil {
+		return nil, err
+	}
+
+	var holidays []Holiday
+	for _, rule := range country.rules {
+		if (rule.from != 0 && year < rule.from) || (rule.to != 0 && year > rule.to) {
+			continue
+		}
+		if len(rule.regions) > 0 && !slices.Contains(rule.regions, region) {
+			continue
+		}
+		holidays = append(holidays, Holiday{Date: rule.date(year), Name: rule.name})
+	}
+	sort.SliceStable(holidays, func(i, j int) bool { return holidays[i].Date.Before(holidays[j].Date) })
+	return holidays, nil
+}
+
+// HolidayOn returns the name of the configured public holiday on date, if any
+func (c *Config) HolidayOn(date time.Time) (string, bool) {
+	if c.Holidays == "" {
+		return "", false
+	}
+	holidays, err := Holidays(c.Holidays, date.Year())
+	if err != nil {
+		return "", false
+	}
+	for _, holiday := range holidays {
+		if holiday.Date.Year() == date.Year() && holiday.Date.YearDay() == date.YearDay() {
+			return holiday.Name, true
+		}
+	}
+	return "", false
+}
+
+// holidayCalendarNames lists the selectable calendars for help and error messages
+func holidayCalendarNames() string {
+	var names []string
+	for code, country := range holidayCountries {
+		names = append(names, code)
+		for _, region := range country.regions {
+			names = append(names, code+"-"+region)
+		}
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEasterSunday(t *testing.T) {
+	for year, want := range map[int]string{
+		2000: "2000-04-23",
+		2019: "2019-04-21",
+		2024: "2024-03-31",
+		2025: "2025-04-20",
+		2038: "2038-04-25",
+	} {
+		if got := easterSunday(year).Format("2006-01-02"); got != want {
+			t.Errorf("Easter %d: expected %s, got %s", year, want, got)
+		}
+	}
+}
+
+func TestGermanHolidays(t *testing.T) {
+	tests := []struct {
+		calendar string
+		year     int
+		count    int
+		date     string
+		name     string
+	}{
+		{"de", 2025, 9, "2025-05-29", "Ascension Day"},
+		{"de-by", 2025, 12, "2025-06-19", "Corpus Christi"},
+		{"de-be", 2025, 10, "2025-03-08", "International Women's Day"},
+		{"de-be", 2018, 9, "", ""},
+		{"de-sn", 2025, 11, "2025-11-19", "Day of Repentance and Prayer"},
+		{"de-nw", 2017, 12, "2017-10-31", "Reformation Day"},
+		{"de", 2017, 10, "2017-10-31", "Reformation Day"},
+		{"de-hh", 2017, 10, "2017-10-31", "Reformation Day"},
+		{"de-hh", 2016, 9, "", ""},
+		{"de-sn", 2017, 11, "2017-10-31", "Reformation Day"}, // Not counted twice
+	}
+	for _, tt := range tests {
+		holidays, err := Holidays(tt.calendar, tt.year)
+		if err != nil {
+			t.Fatalf("Holidays(%s, %d) failed: %v", tt.calendar, tt.year, err)
+		}
+		if len(holidays) != tt.count {
+			t.Errorf("%s %d: expected %d holidays, got %d: %v", tt.calendar, tt.year, tt.count, len(holidays), holidays)
+		}
+		if tt.date == "" {
+			continue
+		}
+		found := false
+		for _, holiday := range holidays {
+			if holiday.Date.Format("2006-01-02") == tt.date && holiday.Name == tt.name {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("%s %d: expected %s on %s", tt.calendar, tt.year, tt.name, tt.date)
+		}
+	}
+
+	if _, err := Holidays("de-xx", 2025); err == nil {
+		t.Error("Unknown region should be rejected")
+	}
+	if err := (&Config{Holidays: "fr"}).Validate(); err == nil {
+		t.Error("Unknown calendar should fail validation")
+	}
+}
+
+func TestHolidayReducesTarget(t *testing.T) {
+	storage := newTestStorage(t)
+	config := &Config{WeeklyTargetHours: 40, FlextimeStart: "2025-04-14", Holidays: "de"}
+	if err := storage.SaveConfig(config); err != nil {
+		t.Fatal(err)
+	}
+	// Easter week 2025: Good Friday and Easter Monday (the following week) are holidays
+	if err := storage.appendSessionsToCSV([]Session{{Date: "2025-04-14", Duration: 8 * 3600}}); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := storage.Flextime(time.Date(2025, 4, 21, 12, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatalf("Flextime failed: %v", err)
+	}
+	if len(report.Weeks) != 2 || report.Weeks[0].Target != 32*time.Hour || report.Weeks[1].Target != 0 {
+		t.Errorf("Expected targets of 32h and 0h, got %+v", report.Weeks)
+	}
+
+	days, err := storage.DailySummaries(time.Date(2025, 4, 18, 0, 0, 0, 0, time.Local), time.Date(2025, 4, 18, 0, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if days[0].Holiday != "Good Friday" {
+		t.Errorf("Expected Good Friday to be marked, got %q", days[0].Holiday)
+	}
+}
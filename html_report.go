@@ -0,0 +1,256 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+)
+
+const (
+	htmlChartWidth   = 720
+	htmlChartHeight  = 200
+	htmlProjectBarH  = 22
+	htmlNoProject    = "(no project)"
+	htmlWorkColor    = "#30a14e"
+	htmlTargetColor  = "#d73a49"
+	htmlProjectColor = "#4078c0"
+)
+
+// svgBar is a rectangle of an inline SVG chart
+type svgBar struct {
+	X, Y, Width, Height float64
+	Label               string // drawn left of the bar at LabelY
+	LabelY              float64
+	Title               string // tooltip
+}
+
+// htmlDayRow is a row of the per-day table
+type htmlDayRow struct {
+	Date, Start, End, Worked, Break, Target, Delta string
+	Note                                           string // holiday or absence
+	Weekend                                        bool
+}
+
+// htmlReportData is the template input, with all values preformatted
+type htmlReportData struct {
+	Title                   string
+	From, To                string
+	Worked, Break, Target   string
+	Delta                   string
+	WorkingDays             int
+	Rows                    []htmlDayRow
+	DayBars                 []svgBar
+	TargetLine              string // SVG polyline points
+	Projects                []svgBar
+	ProjectTable            []htmlProjectRow
+	Surcharges              []htmlSurchargeRow
+	Unclassified            string // worked time without start and end times, empty if none
+	ChartWidth, ChartHeight int
+	ProjectChartHeight      int
+	WorkColor, TargetColor  string
+	ProjectColor            string
+}
+
+type htmlProjectRow struct {
+	Project, Worked, Share string
+}
+
+// htmlSurchargeRow is a row of the surcharge table
+type htmlSurchargeRow struct {
+	Name, Worked, Multiplier, Weighted string
+}
+
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #24292e; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { padding: 4px 10px; border-bottom: 1px solid #e1e4e8; text-align: right; }
+th:first-child, td:first-child { text-align: left; }
+tr.weekend { color: #6a737d; }
+.note { color: #d73a49; font-size: smaller; }
+.summary td { font-weight: bold; }
+svg text { font-size: 11px; fill: #24292e; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>{{.From}} to {{.To}}</p>
+
+<h2>Summary</h2>
+<table class="summary">
+<tr><th>Worked</th><td>{{.Worked}}</td></tr>
+<tr><th>Breaks</th><td>{{.Break}}</td></tr>
+<tr><th>Target</th><td>{{.Target}}</td></tr>
+<tr><th>Overtime</th><td>{{.Delta}}</td></tr>
+<tr><th>Working days</th><td>{{.WorkingDays}}</td></tr>
+</table>
+{{- if .Surcharges}}
+
+<h2>Surcharges</h2>
+<table>
+<tr><th>Bucket</th><th>Worked</th><th>Multiplier</th><th>Weighted</th></tr>
+{{- range .Surcharges}}
+<tr><td>{{.Name}}</td><td>{{.Worked}}</td><td>{{.Multiplier}}</td><td>{{.Weighted}}</td></tr>
+{{- end}}
+</table>
+{{- if .Unclassified}}
+<p class="note">{{.Unclassified}} worked without start and end times could not be classified.</p>
+{{- end}}
+{{- end}}
+
+<h2>Worked Hours per Day</h2>
+<svg xmlns="http://www.w3.org/2000/svg" width="{{.ChartWidth}}" height="{{.ChartHeight}}" viewBox="0 0 {{.ChartWidth}} {{.ChartHeight}}">
+{{- range .DayBars}}
+<rect x="{{printf "%.1f" .X}}" y="{{printf "%.1f" .Y}}" width="{{printf "%.1f" .Width}}" height="{{printf "%.1f" .Height}}" fill="{{$.WorkColor}}"><title>{{.Title}}</title></rect>
+{{- end}}
+{{- if .TargetLine}}
+<polyline points="{{.TargetLine}}" fill="none" stroke="{{.TargetColor}}" stroke-width="1.5"/>
+{{- end}}
+</svg>
+
+<h2>Projects</h2>
+{{- if .Projects}}
+<svg xmlns="http://www.w3.org/2000/svg" width="{{.ChartWidth}}" height="{{.ProjectChartHeight}}" viewBox="0 0 {{.ChartWidth}} {{.ProjectChartHeight}}">
+{{- range .Projects}}
+<text x="0" y="{{printf "%.1f" .LabelY}}">{{.Label}}</text>
+<rect x="{{printf "%.1f" .X}}" y="{{printf "%.1f" .Y}}" width="{{printf "%.1f" .Width}}" height="{{printf "%.1f" .Height}}" fill="{{$.ProjectColor}}"><title>{{.Title}}</title></rect>
+{{- end}}
+</svg>
+{{- end}}
+<table>
+<tr><th>Project</th><th>Worked</th><th>Share</th></tr>
+{{- range .ProjectTable}}
+<tr><td>{{.Project}}</td><td>{{.Worked}}</td><td>{{.Share}}</td></tr>
+{{- end}}
+</table>
+
+<h2>Days</h2>
+<table>
+<tr><th>Date</th><th>Start</th><th>End</th><th>Worked</th><th>Breaks</th><th>Target</th><th>Delta</th></tr>
+{{- range .Rows}}
+<tr{{if .Weekend}} class="weekend"{{end}}><td>{{.Date}}{{if .Note}} <span class="note">{{.Note}}</span>{{end}}</td><td>{{.Start}}</td><td>{{.End}}</td><td>{{.Worked}}</td><td>{{.Break}}</td><td>{{.Target}}</td><td>{{.Delta}}</td></tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
+
+// WriteHTMLReport writes the report as a single HTML file with inline CSS and SVG charts
+func WriteHTMLReport(w io.Writer, report *PeriodReport) error {
+	return htmlReportTemplate.Execute(w, newHTMLReportData(report))
+}
+
+func newHTMLReportData(report *PeriodReport) htmlReportData {
+	data := htmlReportData{
+		Title:        "Working Time Report",
+		From:         report.From.Format("2006-01-02"),
+		To:           report.To.Format("2006-01-02"),
+		Worked:       formatDuration(report.Worked),
+		Break:        formatDuration(report.Break),
+		Target:       formatDuration(report.Target),
+		Delta:        formatSignedDuration(report.Delta()),
+		WorkingDays:  report.WorkingDays,
+		ChartWidth:   htmlChartWidth,
+		ChartHeight:  htmlChartHeight,
+		WorkColor:    htmlWorkColor,
+		TargetColor:  htmlTargetColor,
+		ProjectColor: htmlProjectColor,
+	}
+
+	for _, day := range report.Days {
+		if day.Worked == 0 && day.Target == 0 && len(day.Sessions) == 0 && day.Note() == "" {
+			continue
+		}
+		data.Rows = append(data.Rows, htmlDayRow{
+			Date:    day.Date.Format("Mon 2006-01-02"),
+			Start:   formatClock(day.FirstStart()),
+			End:     formatClock(day.LastEnd()),
+			Worked:  formatDuration(day.Worked),
+			Break:   formatDuration(day.Break),
+			Target:  formatDuration(day.Target),
+			Delta:   formatSignedDuration(day.Worked - day.Target),
+			Note:    day.Note(),
+			Weekend: day.Target == 0,
+		})
+	}
+
+	for _, bucket := range report.Surcharges {
+		data.Surcharges = append(data.Surcharges, htmlSurchargeRow{
+			Name:       bucket.Rule.Name,
+			Worked:     formatDuration(bucket.Worked),
+			Multiplier: fmt.Sprintf("×%g", bucket.Rule.Multiplier),
+			Weighted:   formatDuration(bucket.Weighted()),
+		})
+	}
+	if len(report.Surcharges) > 0 && report.Unclassified > 0 {
+		data.Unclassified = formatDuration(report.Unclassified)
+	}
+
+	data.DayBars, data.TargetLine = htmlDayChart(report.Days)
+	// Project names take the left quarter, bars are scaled to the largest project
+	barX := float64(htmlChartWidth) / 4
+	data.ProjectChartHeight = len(report.Projects) * htmlProjectBarH
+	for i, project := range report.Projects {
+		name := project.Project
+		if name == "" {
+			name = htmlNoProject
+		}
+		share := 0.0
+		if report.Worked > 0 {
+			share = float64(project.Worked) / float64(report.Worked)
+		}
+		width := 0.0
+		if top := report.Projects[0].Worked; top > 0 {
+			width = (htmlChartWidth - barX) * float64(project.Worked) / float64(top)
+		}
+		data.Projects = append(data.Projects, svgBar{
+			X: barX, Y: float64(i * htmlProjectBarH), Width: width, Height: htmlProjectBarH - 4,
+			Label: name, LabelY: float64(i*htmlProjectBarH) + 13, Title: fmt.Sprintf("%s: %s", name, formatDuration(project.Worked)),
+		})
+		data.ProjectTable = append(data.ProjectTable, htmlProjectRow{
+			Project: name, Worked: formatDuration(project.Worked), Share: fmt.Sprintf("%.1f%%", share*100),
+		})
+	}
+	return data
+}
+
+// htmlDayChart returns one bar per day and the daily target as SVG polyline points
+func htmlDayChart(days []DaySummary) ([]svgBar, string) {
+	if len(days) == 0 {
+		return nil, ""
+	}
+	max := time.Hour
+	for _, day := range days {
+		if day.Worked > max {
+			max = day.Worked
+		}
+		if day.Target > max {
+			max = day.Target
+		}
+	}
+	scale := htmlChartHeight / (max.Hours() * 1.1)
+	slot := float64(htmlChartWidth) / float64(len(days))
+
+	var bars []svgBar
+	var points []byte
+	hasTarget := false
+	for i, day := range days {
+		height := day.Worked.Hours() * scale
+		bars = append(bars, svgBar{
+			X: slot*float64(i) + slot*0.1, Y: htmlChartHeight - height, Width: slot * 0.8, Height: height,
+			Title: fmt.Sprintf("%s: %s", day.Date.Format("2006-01-02"), formatDuration(day.Worked)),
+		})
+		y := htmlChartHeight - day.Target.Hours()*scale
+		points = fmt.Appendf(points, "%.1f,%.1f %.1f,%.1f ", slot*float64(i), y, slot*float64(i+1), y)
+		hasTarget = hasTarget || day.Target > 0
+	}
+	if !hasTarget {
+		return bars, ""
+	}
+	return bars, string(points[:len(points)-1])
+}
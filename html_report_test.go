@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHTMLReport(t *testing.T) {
+	storage := newTestStorage(t)
+	start := time.Date(2025, 3, 10, 9, 0, 0, 0, time.Local)
+	sessions := []Session{
+		{Date: "2025-03-10", Duration: 5 * 3600, BreakTime: 1800, Start: start, End: start.Add(5 * time.Hour), Project: "Website"},
+		{Date: "2025-03-11", Duration: 3 * 3600, Project: "<Intranet>"},
+		{Date: "2025-03-11", Duration: 3600},
+	}
+	if err := storage.appendSessionsToCSV(sessions); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := storage.PeriodReport(time.Time{}, start.AddDate(0, 0, 6))
+	if err != nil {
+		t.Fatalf("PeriodReport failed: %v", err)
+	}
+	if !report.From.Equal(time.Date(2025, 3, 10, 0, 0, 0, 0, time.Local)) || len(report.Days) != 7 {
+		t.Errorf("Expected the report to start at the first session and cover 7 days, got %v and %d days", report.From, len(report.Days))
+	}
+	if report.Worked != 8*time.Hour+30*time.Minute || report.Target != 40*time.Hour || report.WorkingDays != 2 {
+		t.Errorf("Unexpected totals: %+v", report)
+	}
+	if len(report.Projects) != 3 || report.Projects[0].Project != "Website" || report.Projects[2].Project != "" {
+		t.Errorf("Expected projects ordered by worked time, got %+v", report.Projects)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteHTMLReport(&buf, report); err != nil {
+		t.Fatalf("WriteHTMLReport failed: %v", err)
+	}
+	html := buf.String()
+	for _, want := range []string{"8:30:00", "-31:30:00", "<svg", "<polyline", "Mon 2025-03-10", "09:00", "14:00", htmlNoProject, "&lt;Intranet&gt;"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Report should contain %q", want)
+		}
+	}
+	if strings.Contains(html, "<Intranet>") || strings.Contains(html, "http-equiv") || strings.Contains(html, "src=") {
+		t.Error("Report should escape project names and not reference external resources")
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	icsProductID  = "-//LitschiW//Time Tracker//EN"
+	icsUIDDomain  = "timetracker.litschiw.github.io"
+	icsTimeFormat = "20060102T150405Z"
+	icsDateFormat = "20060102"
+)
+
+// WriteICS writes the sessions and their breaks as an RFC 5545 calendar.
+// UIDs are derived from the session itself, so re-exporting updates existing events.
+func WriteICS(w io.Writer, sessions []Session) error {
+	ics := &icsWriter{w: bufio.NewWriter(w)}
+
+	ics.line("BEGIN:VCALENDAR")
+	ics.line("VERSION:2.0")
+	ics.line("PRODID:" + icsProductID)
+	ics.line("CALSCALE:GREGORIAN")
+	ics.line("X-WR-CALNAME:" + icsEscape(windowTitle))
+
+	for _, session := range sessions {
+		date, err := time.Parse("2006-01-02", session.Date)
+		if err != nil {
+			continue // Skip invalid dates
+		}
+
+		uid := icsUID(sessionKey(session))
+		summary := "Work"
+		if session.Project != "" {
+			summary += ": " + session.Project
+		}
+		description := fmt.Sprintf("Worked %s, break %s",
+			formatDuration(time.Duration(session.Duration-session.BreakTime)*time.Second),
+			formatDuration(time.Duration(session.BreakTime)*time.Second))
+		if session.Description != "" {
+			description += "\n" + session.Description
+		}
+
+		ics.line("BEGIN:VEVENT")
+		ics.line("UID:" + uid)
+		if session.Start.IsZero() {
+			// Without start times only the day is known
+			ics.line("DTSTAMP:" + date.UTC().Format(icsTimeFormat))
+			ics.line("DTSTART;VALUE=DATE:" + date.Format(icsDateFormat))
+			ics.line("DTEND;VALUE=DATE:" + date.AddDate(0, 0, 1).Format(icsDateFormat))
+		} else {
+			end := session.End
+			if end.IsZero() {
+				end = session.Start.Add(time.Duration(session.Duration) * time.Second)
+			}
+			ics.line("DTSTAMP:" + end.UTC().Format(icsTimeFormat))
+			ics.line("DTSTART:" + session.Start.UTC().Format(icsTimeFormat))
+			ics.line("DTEND:" + end.UTC().Format(icsTimeFormat))
+		}
+		ics.line("SUMMARY:" + icsEscape(summary))
+		ics.line("DESCRIPTION:" + icsEscape(description))
+		ics.line("TRANSP:OPAQUE")
+		ics.line("END:VEVENT")
+
+		for i, b := range session.Breaks {
+			ics.line("BEGIN:VEVENT")
+			ics.line(fmt.Sprintf("UID:%s", icsUID(fmt.Sprintf("%s|break|%d", sessionKey(session), i))))
+			ics.line("DTSTAMP:" + b.End.UTC().Format(icsTimeFormat))
+			ics.line("DTSTART:" + b.Start.UTC().Format(icsTimeFormat))
+			ics.line("DTEND:" + b.End.UTC().Format(icsTimeFormat))
+			ics.line("SUMMARY:Break")
+			ics.line("RELATED-TO:" + uid)
+			ics.line("TRANSP:TRANSPARENT")
+			ics.line("END:VEVENT")
+		}
+	}
+
+	ics.line("END:VCALENDAR")
+	if ics.err != nil {
+		return ics.err
+	}
+	return ics.w.Flush()
+}
+
+// icsWriter writes CRLF-terminated content lines, folded at 75 octets
+type icsWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (ics *icsWriter) line(content string) {
+	if ics.err != nil {
+		return
+	}
+	for len(content) > 75 {
+		// Fold without splitting a UTF-8 sequence
+		cut := 75
+		for cut > 0 && content[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		_, ics.err = ics.w.WriteString(content[:cut] + "\r\n")
+		content = " " + content[cut:]
+	}
+	_, ics.err = ics.w.WriteString(content + "\r\n")
+}
+
+// icsUID returns a stable event UID for the given key
+func icsUID(key string) string {
+	return fmt.Sprintf("%x@%s", sha1.Sum([]byte(key)), icsUIDDomain)
+}
+
+// icsEscape escapes a TEXT property value
+func icsEscape(value string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\n", `\n`,
+	).Replace(value)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteICS(t *testing.T) {
+	start := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	sessions := []Session{
+		{
+			Date:        "2025-03-10",
+			Duration:    4 * 3600,
+			BreakTime:   1800,
+			Start:       start,
+			End:         start.Add(4 * time.Hour),
+			Project:     "Website",
+			Description: "Header, footer; and " + strings.Repeat("more ", 20),
+			Breaks:      []Break{{Start: start.Add(2 * time.Hour), End: start.Add(150 * time.Minute)}},
+		},
+		{Date: "2025-03-11", Duration: 3600},
+	}
+
+	var first, second bytes.Buffer
+	if err := WriteICS(&first, sessions); err != nil {
+		t.Fatalf("WriteICS failed: %v", err)
+	}
+	if err := WriteICS(&second, sessions); err != nil {
+		t.Fatalf("WriteICS failed: %v", err)
+	}
+	if first.String() != second.String() {
+		t.Error("Exporting the same sessions twice should give identical output")
+	}
+
+	ics := first.String()
+	if strings.Count(ics, "BEGIN:VEVENT") != 3 {
+		t.Errorf("Expected 3 events (2 sessions, 1 break), got:\n%s", ics)
+	}
+	for _, want := range []string{
+		"DTSTART:20250310T090000Z",
+		"DTEND:20250310T130000Z",
+		"DTSTART:20250310T110000Z", // break
+		"DTSTART;VALUE=DATE:20250311",
+		`Header\, footer\; and`,
+	} {
+		if !strings.Contains(ics, want) {
+			t.Errorf("Expected output to contain %q", want)
+		}
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(ics, "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("Line exceeds 75 octets: %q", line)
+		}
+		if strings.Contains(line, "\n") {
+			t.Errorf("Line not terminated with CRLF: %q", line)
+		}
+	}
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ImportAdapter maps rows of a third-party CSV export onto sessions
+type ImportAdapter interface {
+	Name() string
+	// Detect reports whether a header row belongs to this export format
+	Detect(header []string) bool
+	ParseRow(row map[string]string) (Session, error)
+}
+
+var importAdapters = []ImportAdapter{
+	togglAdapter{},
+	clockifyAdapter{},
+	harvestAdapter{},
+}
+
+// ImportResult summarizes an import run
+type ImportResult struct {
+	Format     string
+	Sessions   []Session // sessions that are new and were (or would be) stored
+	Duplicates int
+	Skipped    []string // rows that could not be parsed, with reason
+}
+
+// findImportAdapter returns the adapter with the given name, or detects it from the header if name is empty
+func findImportAdapter(name string, header []string) (ImportAdapter, error) {
+	for _, adapter := range importAdapters {
+		if name != "" {
+			if strings.EqualFold(adapter.Name(), name) {
+				return adapter, nil
+			}
+			continue
+		}
+		if adapter.Detect(header) {
+			return adapter, nil
+		}
+	}
+	if name != "" {
+		return nil, fmt.Errorf("unknown import format %q", name)
+	}
+	return nil, fmt.Errorf("could not detect import format from header")
+}
+
+// ParseImport reads a CSV export and converts its rows to sessions
+func ParseImport(r io.Reader, format string) (*ImportResult, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff"))
+	}
+
+	adapter, err := findImportAdapter(format, header)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{Format: adapter.Name()}
+	line := 1
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		row := make(map[string]string, len(header))
+		for i, column := range header {
+			if i < len(record) {
+				row[column] = strings.TrimSpace(record[i])
+			}
+		}
+
+		session, err := adapter.ParseRow(row)
+		if err != nil {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("line %d: %v", line, err))
+			continue
+		}
+		result.Sessions = append(result.Sessions, session)
+	}
+
+	return result, nil
+}
+
+// ImportSessions stores the parsed sessions that are not already in the history.
+// With dryRun set, nothing is written and the result only previews the import.
+func (s *Storage) ImportSessions(result *ImportResult, dryRun bool) error {
+	existing, err := s.loadSessionsFromCSV()
+	if err != nil {
+		return fmt.Errorf("failed to load existing sessions: %w", err)
+	}
+	config, err := s.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool, len(existing))
+	for _, session := range existing {
+		seen[sessionKey(session)] = true
+	}
+
+	var fresh []Session
+	for _, session := range result.Sessions {
+		if !session.Start.IsZero() {
+			// Store the session under its working day, like sessions tracked by the timer
+			session.Date = config.LogicalDate(session.Start)
+			if session.Zone == "" {
+				session.Zone = recordedZone(session.Start)
+			}
+		}
+		key := sessionKey(session)
+		if seen[key] {
+			result.Duplicates++
+			continue
+		}
+		seen[key] = true
+		fresh = append(fresh, session)
+	}
+	result.Sessions = fresh
+
+	if dryRun || len(fresh) == 0 {
+		return nil
+	}
+	return s.appendSessionsToCSV(fresh)
+}
+
+// sessionKey identifies a session for deduplication
+func sessionKey(session Session) string {
+	if !session.Start.IsZero() {
+		return fmt.Sprintf("%d|%d", session.Start.Unix(), session.Duration)
+	}
+	return fmt.Sprintf("%s|%d|%s|%s", session.Date, session.Duration, session.Project, session.Description)
+}
+
+// hasColumns reports whether all columns are present in the header
+func hasColumns(header []string, columns ...string) bool {
+	present := make(map[string]bool, len(header))
+	for _, column := range header {
+		present[column] = true
+	}
+	for _, column := range columns {
+		if !present[column] {
+			return false
+		}
+	}
+	return true
+}
+
+// parseClockDuration parses durations like "1:30:00" or "01:30"
+func parseClockDuration(value string) (time.Duration, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	var total time.Duration
+	units := []time.Duration{time.Hour, time.Minute, time.Second}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		total += time.Duration(n) * units[i]
+	}
+	return total, nil
+}
+
+// parseLocalDateTime parses a date and a time column using the first matching layouts
+func parseLocalDateTime(date, clock string, dateLayouts, clockLayouts []string) (time.Time, error) {
+	for _, dateLayout := range dateLayouts {
+		for _, clockLayout := range clockLayouts {
+			if t, err := time.ParseInLocation(dateLayout+" "+clockLayout, date+" "+clock, time.Local); err == nil {
+				return t, nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date/time %q %q", date, clock)
+}
+
+// sessionFromRange builds a session from start and end times, using duration if given
+func sessionFromRange(start, end time.Time, duration time.Duration, project, description string) Session {
+	if duration <= 0 {
+		duration = end.Sub(start)
+	}
+	return Session{
+		Date:        start.Format("2006-01-02"),
+		Duration:    int64(duration.Seconds()),
+		Start:       start,
+		End:         end,
+		Project:     project,
+		Description: description,
+	}
+}
+
+// togglAdapter reads the Toggl Track "Detailed" CSV export
+type togglAdapter struct{}
+
+func (togglAdapter) Name() string { return "toggl" }
+
+func (togglAdapter) Detect(header []string) bool {
+	return hasColumns(header, "Start date", "Start time", "End date", "End time", "Duration")
+}
+
+func (togglAdapter) ParseRow(row map[string]string) (Session, error) {
+	dateLayouts := []string{"2006-01-02", "01/02/2006", "02.01.2006"}
+	clockLayouts := []string{"15:04:05", "03:04:05 PM"}
+
+	start, err := parseLocalDateTime(row["Start date"], row["Start time"], dateLayouts, clockLayouts)
+	if err != nil {
+		return Session{}, err
+	}
+	end, err := parseLocalDateTime(row["End date"], row["End time"], dateLayouts, clockLayouts)
+	if err != nil {
+		return Session{}, err
+	}
+	duration, err := parseClockDuration(row["Duration"])
+	if err != nil {
+		return Session{}, err
+	}
+	return sessionFromRange(start, end, duration, row["Project"], row["Description"]), nil
+}
+
+// clockifyAdapter reads the Clockify "Detailed report" CSV export
+type clockifyAdapter struct{}
+
+func (clockifyAdapter) Name() string { return "clockify" }
+
+func (clockifyAdapter) Detect(header []string) bool {
+	return hasColumns(header, "Start Date", "Start Time", "End Date", "End Time", "Duration (h)")
+}
+
+func (clockifyAdapter) ParseRow(row map[string]string) (Session, error) {
+	dateLayouts := []string{"01/02/2006", "2006-01-02", "02.01.2006", "02/01/2006"}
+	clockLayouts := []string{"03:04:05 PM", "15:04:05", "03:04 PM", "15:04"}
+
+	start, err := parseLocalDateTime(row["Start Date"], row["Start Time"], dateLayouts, clockLayouts)
+	if err != nil {
+		return Session{}, err
+	}
+	end, err := parseLocalDateTime(row["End Date"], row["End Time"], dateLayouts, clockLayouts)
+	if err != nil {
+		return Session{}, err
+	}
+	duration, err := parseClockDuration(row["Duration (h)"])
+	if err != nil {
+		return Session{}, err
+	}
+	return sessionFromRange(start, end, duration, row["Project"], row["Description"]), nil
+}
+
+// harvestAdapter reads the Harvest "Detailed time" CSV export, which has no start or end times
+type harvestAdapter struct{}
+
+func (harvestAdapter) Name() string { return "harvest" }
+
+func (harvestAdapter) Detect(header []string) bool {
+	return hasColumns(header, "Date", "Hours", "Notes", "Project")
+}
+
+func (harvestAdapter) ParseRow(row map[string]string) (Session, error) {
+	date, err := time.ParseInLocation("2006-01-02", row["Date"], time.Local)
+	if err != nil {
+		return Session{}, fmt.Errorf("invalid date %q", row["Date"])
+	}
+	hours, err := strconv.ParseFloat(row["Hours"], 64)
+	if err != nil || hours <= 0 {
+		return Session{}, fmt.Errorf("invalid hours %q", row["Hours"])
+	}
+	return Session{
+		Date:        date.Format("2006-01-02"),
+		Duration:    int64(math.Round(hours * 3600)),
+		Project:     row["Project"],
+		Description: row["Notes"],
+	}, nil
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestStorage(t *testing.T) *Storage {
+	dir := t.TempDir()
+	return &Storage{
+		jsonFile:     filepath.Join(dir, "current_session.json"),
+		csvFile:      filepath.Join(dir, "sessions.csv"),
+		configFile:   filepath.Join(dir, "config.json"),
+		absencesFile: filepath.Join(dir, "absences.csv"),
+		bookingsFile: filepath.Join(dir, "bookings.csv"),
+		invoicesFile: filepath.Join(dir, "invoices.json"),
+	}
+}
+
+func TestImportFormats(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		csv      string
+		duration int64
+		hasStart bool
+	}{
+		{
+			name:   "toggl",
+			format: "toggl",
+			csv: "User,Email,Client,Project,Task,Description,Billable,Start date,Start time,End date,End time,Duration,Tags,Amount ()\n" +
+				"Jane,jane@example.com,ACME,Website,,Fix header,Yes,2025-03-10,09:00:00,2025-03-10,10:30:00,01:30:00,,\n",
+			duration: 5400,
+			hasStart: true,
+		},
+		{
+			name:   "clockify",
+			format: "clockify",
+			csv: "Project,Client,Description,Task,User,Group,Email,Tags,Billable,Start Date,Start Time,End Date,End Time,Duration (h),Duration (decimal)\n" +
+				"Website,ACME,Fix header,,Jane,,jane@example.com,,Yes,03/10/2025,09:00:00 AM,03/10/2025,10:30:00 AM,01:30:00,1.50\n",
+			duration: 5400,
+			hasStart: true,
+		},
+		{
+			name:   "harvest",
+			format: "harvest",
+			csv: "Date,Client,Project,Project Code,Task,Notes,Hours,Hours Rounded,Billable?\n" +
+				"2025-03-10,ACME,Website,,Development,Fix header,1.5,1.5,Yes\n",
+			duration: 5400,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseImport(strings.NewReader(tt.csv), "")
+			if err != nil {
+				t.Fatalf("ParseImport failed: %v", err)
+			}
+			if result.Format != tt.format {
+				t.Errorf("Expected format %s, got %s", tt.format, result.Format)
+			}
+			if len(result.Sessions) != 1 {
+				t.Fatalf("Expected 1 session, got %d (skipped: %v)", len(result.Sessions), result.Skipped)
+			}
+
+			session := result.Sessions[0]
+			if session.Date != "2025-03-10" {
+				t.Errorf("Expected date 2025-03-10, got %s", session.Date)
+			}
+			if session.Duration != tt.duration {
+				t.Errorf("Expected duration %d, got %d", tt.duration, session.Duration)
+			}
+			if session.Project != "Website" || session.Description != "Fix header" {
+				t.Errorf("Unexpected project/description: %q/%q", session.Project, session.Description)
+			}
+			if tt.hasStart && session.Start.Format("15:04") != "09:00" {
+				t.Errorf("Expected start 09:00, got %v", session.Start)
+			}
+		})
+	}
+}
+
+func TestImportDeduplicationAndDryRun(t *testing.T) {
+	storage := newTestStorage(t)
+	csv := "Date,Client,Project,Project Code,Task,Notes,Hours\n" +
+		"2025-03-10,ACME,Website,,Dev,Fix header,1.5\n" +
+		"2025-03-10,ACME,Website,,Dev,Fix header,1.5\n" +
+		"2025-03-11,ACME,Website,,Dev,Fix footer,2\n" +
+		"not-a-date,ACME,Website,,Dev,Broken,1\n"
+
+	result, err := ParseImport(strings.NewReader(csv), "harvest")
+	if err != nil {
+		t.Fatalf("ParseImport failed: %v", err)
+	}
+	if len(result.Skipped) != 1 {
+		t.Errorf("Expected 1 skipped row, got %v", result.Skipped)
+	}
+
+	// Dry run must not write anything
+	if err := storage.ImportSessions(result, true); err != nil {
+		t.Fatalf("Dry run failed: %v", err)
+	}
+	if len(result.Sessions) != 2 || result.Duplicates != 1 {
+		t.Errorf("Expected 2 new sessions and 1 duplicate, got %d and %d", len(result.Sessions), result.Duplicates)
+	}
+	if _, err := os.Stat(storage.csvFile); !os.IsNotExist(err) {
+		t.Error("Dry run should not create the sessions file")
+	}
+
+	// Real import, then importing again finds only duplicates
+	result, _ = ParseImport(strings.NewReader(csv), "harvest")
+	if err := storage.ImportSessions(result, false); err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	result, _ = ParseImport(strings.NewReader(csv), "harvest")
+	if err := storage.ImportSessions(result, false); err != nil {
+		t.Fatalf("Second import failed: %v", err)
+	}
+	if len(result.Sessions) != 0 || result.Duplicates != 3 {
+		t.Errorf("Expected only duplicates on re-import, got %d new and %d duplicates", len(result.Sessions), result.Duplicates)
+	}
+
+	sessions, err := storage.loadSessionsFromCSV()
+	if err != nil {
+		t.Fatalf("Failed to load sessions: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Errorf("Expected 2 stored sessions, got %d", len(sessions))
+	}
+}
+
+func TestLegacyCSVFormat(t *testing.T) {
+	storage := newTestStorage(t)
+	legacy := "date,duration_s,break_time_s\n2025-03-10,3600,600\n"
+	if err := os.WriteFile(storage.csvFile, []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Appending new-format rows to an old file must keep both readable
+	start := time.Date(2025, 3, 11, 9, 0, 0, 0, time.Local)
+	if err := storage.appendSessionsToCSV([]Session{{Date: "2025-03-11", Duration: 60, Start: start, Project: "Website"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	sessions, err := storage.loadSessionsFromCSV()
+	if err != nil {
+		t.Fatalf("Failed to load sessions: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("Expected 2 sessions, got %d", len(sessions))
+	}
+	if sessions[0].BreakTime != 600 || !sessions[0].Start.IsZero() {
+		t.Errorf("Unexpected legacy session: %+v", sessions[0])
+	}
+	if !sessions[1].Start.Equal(start) || sessions[1].Project != "Website" {
+		t.Errorf("Unexpected new session: %+v", sessions[1])
+	}
+
+	data, err := os.ReadFile(storage.csvFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header, _, _ := strings.Cut(string(data), "\n"); header != strings.Join(csvHeader, ",") {
+		t.Errorf("Expected the header to name the new columns, got %q", header)
+	}
+}
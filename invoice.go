@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"strings"
+)
+
+// invoiceData is the template input, with all values preformatted
+type invoiceData struct {
+	Number, Date, From, To string
+	Issuer                 []string
+	Client                 []string
+	Lines                  []invoiceLineRow
+	Net, VAT, Total        string
+	VATPercent             string
+	Rounding               string // description of the rounding policy, empty if exact
+}
+
+type invoiceLineRow struct {
+	Project, Sessions, Hours, Rate, Amount string
+}
+
+func newInvoiceData(invoice *Invoice) invoiceData {
+	data := invoiceData{
+		Number:     invoice.Number,
+		Date:       invoice.Date.Format("2006-01-02"),
+		From:       invoice.From.Format("2006-01-02"),
+		To:         invoice.To.Format("2006-01-02"),
+		Client:     []string{invoice.Client.Name},
+		Net:        formatMoney(invoice.Net, invoice.Currency),
+		VAT:        formatMoney(invoice.VAT, invoice.Currency),
+		Total:      formatMoney(invoice.Total, invoice.Currency),
+		VATPercent: fmt.Sprintf("%g%%", invoice.VATPercent),
+		Rounding:   invoice.Rounding.String(),
+	}
+	if invoice.Issuer != "" {
+		data.Issuer = strings.Split(invoice.Issuer, "\n")
+	}
+	if invoice.Client.Name == "" {
+		data.Client[0] = invoice.ClientID
+	}
+	if invoice.Client.Address != "" {
+		data.Client = append(data.Client, strings.Split(invoice.Client.Address, "\n")...)
+	}
+	for _, line := range invoice.Lines {
+		project := line.Project
+		if project == "" {
+			project = htmlNoProject
+		}
+		data.Lines = append(data.Lines, invoiceLineRow{
+			Project:  project,
+			Sessions: fmt.Sprint(line.Sessions),
+			Hours:    formatHours(line.Hours),
+			Rate:     formatMoney(int64(math.Round(line.Rate*100)), invoice.Currency),
+			Amount:   formatMoney(line.Amount, invoice.Currency),
+		})
+	}
+	return data
+}
+
+var invoiceHTMLTemplate = template.Must(template.New("invoice").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Invoice {{.Number}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #24292e; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { padding: 4px 10px; border-bottom: 1px solid #e1e4e8; text-align: right; }
+th:first-child, td:first-child { text-align: left; }
+.address { margin-bottom: 2em; }
+.total td { font-weight: bold; }
+.note { color: #6a737d; font-size: smaller; }
+</style>
+</head>
+<body>
+{{- if .Issuer}}
+<div class="address">{{range $i, $line := .Issuer}}{{if $i}}<br>{{end}}{{$line}}{{end}}</div>
+{{- end}}
+<div class="address">{{range $i, $line := .Client}}{{if $i}}<br>{{end}}{{$line}}{{end}}</div>
+<h1>Invoice {{.Number}}</h1>
+<p>Date: {{.Date}}<br>Period: {{.From}} to {{.To}}</p>
+
+<table>
+<tr><th>Project</th><th>Sessions</th><th>Hours</th><th>Rate</th><th>Amount</th></tr>
+{{- range .Lines}}
+<tr><td>{{.Project}}</td><td>{{.Sessions}}</td><td>{{.Hours}}</td><td>{{.Rate}}</td><td>{{.Amount}}</td></tr>
+{{- end}}
+<tr><td colspan="4">Net</td><td>{{.Net}}</td></tr>
+<tr><td colspan="4">VAT {{.VATPercent}}</td><td>{{.VAT}}</td></tr>
+<tr class="total"><td colspan="4">Total</td><td>{{.Total}}</td></tr>
+</table>
+{{- if .Rounding}}
+<p class="note">{{.Rounding}}</p>
+{{- end}}
+</body>
+</html>
+`))
+
+// WriteInvoiceHTML writes the invoice as a single HTML file with inline CSS
+func WriteInvoiceHTML(w io.Writer, invoice *Invoice) error {
+	return invoiceHTMLTemplate.Execute(w, newInvoiceData(invoice))
+}
+
+// WriteInvoiceMarkdown writes the invoice as a Markdown document
+func WriteInvoiceMarkdown(w io.Writer, invoice *Invoice) error {
+	data := newInvoiceData(invoice)
+	// Pipes would end table cells early
+	escape := strings.NewReplacer("|", "\\|").Replace
+
+	bw := bufio.NewWriter(w)
+	if len(data.Issuer) > 0 {
+		fmt.Fprintf(bw, "%s\n\n", strings.Join(data.Issuer, "  \n"))
+	}
+	fmt.Fprintf(bw, "%s\n\n", strings.Join(data.Client, "  \n"))
+	fmt.Fprintf(bw, "# Invoice %s\n\n", data.Number)
+	fmt.Fprintf(bw, "Date: %s  \nPeriod: %s to %s\n\n", data.Date, data.From, data.To)
+	fmt.Fprintln(bw, "| Project | Sessions | Hours | Rate | Amount |")
+	fmt.Fprintln(bw, "|---|---:|---:|---:|---:|")
+	for _, line := range data.Lines {
+		fmt.Fprintf(bw, "| %s | %s | %s | %s | %s |\n", escape(line.Project), line.Sessions, line.Hours, line.Rate, line.Amount)
+	}
+	fmt.Fprintf(bw, "| Net | | | | %s |\n", data.Net)
+	fmt.Fprintf(bw, "| VAT %s | | | | %s |\n", data.VATPercent, data.VAT)
+	fmt.Fprintf(bw, "| **Total** | | | | **%s** |\n", data.Total)
+	if data.Rounding != "" {
+		fmt.Fprintf(bw, "\n%s\n", data.Rounding)
+	}
+	return bw.Flush()
+}
+
+// writeInvoice picks the format by the file extension, HTML unless it ends in ".md"
+func writeInvoice(w io.Writer, name string, invoice *Invoice) error {
+	if strings.HasSuffix(strings.ToLower(name), ".md") {
+		return WriteInvoiceMarkdown(w, invoice)
+	}
+	return WriteInvoiceHTML(w, invoice)
+}
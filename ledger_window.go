@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	ledgerWindowTitle  = "Balance Ledger"
+	ledgerWindowWidth  = 640
+	ledgerWindowHeight = 480
+)
+
+// LedgerWindow lists every booking of the flextime balance and records payouts and corrections
+type LedgerWindow struct {
+	window      fyne.Window
+	storage     *Storage
+	kindSelect  *widget.Select
+	dateEntry   *widget.Entry
+	hoursEntry  *widget.Entry
+	reasonEntry *widget.Entry
+	list        *widget.List
+	totalsLabel *widget.Label
+	entries     []LedgerEntry
+}
+
+func NewLedgerWindow(app fyne.App, storage *Storage) *LedgerWindow {
+	lw := &LedgerWindow{
+		window:      app.NewWindow(ledgerWindowTitle),
+		storage:     storage,
+		kindSelect:  widget.NewSelect([]string{bookingPayout, bookingCorrection}, nil),
+		dateEntry:   widget.NewEntry(),
+		hoursEntry:  widget.NewEntry(),
+		reasonEntry: widget.NewEntry(),
+		totalsLabel: widget.NewLabel(""),
+	}
+	lw.kindSelect.SetSelected(bookingPayout)
+	today, err := storage.Today()
+	if err != nil {
+		today = time.Now()
+	}
+	lw.dateEntry.SetText(today.Format("2006-01-02"))
+	lw.hoursEntry.SetPlaceHolder("e.g. 8 or -1.5")
+
+	lw.list = widget.NewList(
+		func() int { return len(lw.entries) },
+		func() fyne.CanvasObject {
+			return widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Monospace: true})
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			item.(*widget.Label).SetText(formatLedgerEntry(lw.entries[id]))
+		},
+	)
+
+	form := widget.NewForm(
+		widget.NewFormItem("Kind", lw.kindSelect),
+		widget.NewFormItem("Date", lw.dateEntry),
+		widget.NewFormItem("Hours", lw.hoursEntry),
+		widget.NewFormItem("Reason", lw.reasonEntry),
+	)
+	top := container.NewVBox(form, container.NewHBox(widget.NewButton("Book", lw.book)))
+
+	lw.window.SetContent(container.NewBorder(top, lw.totalsLabel, nil, nil, lw.list))
+	lw.window.Resize(fyne.NewSize(ledgerWindowWidth, ledgerWindowHeight))
+	lw.refresh()
+
+	return lw
+}
+
+// book records the entered payout or correction; payouts are entered as positive hours
+func (lw *LedgerWindow) book() {
+	hours, err := strconv.ParseFloat(lw.hoursEntry.Text, 64)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("invalid hours %q", lw.hoursEntry.Text), lw.window)
+		return
+	}
+	booking := Booking{Date: lw.dateEntry.Text, Kind: lw.kindSelect.Selected, Amount: hoursToDuration(hours), Reason: lw.reasonEntry.Text}
+	if booking.Kind == bookingPayout {
+		booking.Amount = -booking.Amount
+	}
+	if err := lw.storage.AddBooking(booking); err != nil {
+		dialog.ShowError(err, lw.window)
+		return
+	}
+	lw.hoursEntry.SetText("")
+	lw.reasonEntry.SetText("")
+	lw.refresh()
+}
+
+// refresh reloads the bookings and the balance
+func (lw *LedgerWindow) refresh() {
+	today, err := lw.storage.Today()
+	if err != nil {
+		lw.totalsLabel.SetText("Error: " + err.Error())
+		return
+	}
+	ledger, err := lw.storage.Ledger(today)
+	if err != nil {
+		lw.totalsLabel.SetText("Error: " + err.Error())
+		return
+	}
+	lw.entries = ledger.Entries
+	lw.list.Refresh()
+	lw.totalsLabel.SetText(formatLedgerTotals(ledger))
+}
+
+func (lw *LedgerWindow) Show() {
+	lw.window.Show()
+}
@@ -2,14 +2,21 @@ package main
 
 import (
 	"log"
+	"os"
 
 	"fyne.io/fyne/v2/app"
 )
 
 func main() {
-	application := app.New()
 	storage := NewStorage()
 
+	// Run a subcommand instead of the GUI if one is given
+	if len(os.Args) > 1 {
+		os.Exit(runCLI(storage, os.Args[1:], os.Stdout))
+	}
+
+	application := app.New()
+
 	// Load or create new timer
 	timer, err := storage.LoadTimer()
 	if err != nil {
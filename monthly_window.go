@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	monthlyWindowTitle  = "Monthly History"
+	monthlyWindowWidth  = 450
+	monthlyWindowHeight = 350
+)
+
+var monthlyColumns = []string{"Month", "Worked", "Breaks", "Days"}
+
+// NewMonthlyWindow creates a window listing worked hours, break hours, working days and surcharge buckets per month
+func NewMonthlyWindow(app fyne.App, storage *Storage) fyne.Window {
+	window := app.NewWindow(monthlyWindowTitle)
+
+	summaries, err := storage.MonthlySummaries()
+	if err != nil {
+		window.SetContent(widget.NewLabel("Error: " + err.Error()))
+		return window
+	}
+	config, err := storage.LoadConfig()
+	if err != nil {
+		window.SetContent(widget.NewLabel("Error: " + err.Error()))
+		return window
+	}
+	// One column per surcharge rule follows the fixed columns
+	columns := append([]string{}, monthlyColumns...)
+	for _, rule := range config.Surcharges {
+		columns = append(columns, rule.Label())
+	}
+
+	table := widget.NewTable(
+		func() (int, int) { return len(summaries) + 1, len(columns) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.TableCellID, cell fyne.CanvasObject) {
+			label := cell.(*widget.Label)
+			if id.Row == 0 {
+				label.TextStyle = fyne.TextStyle{Bold: true}
+				label.SetText(columns[id.Col])
+				return
+			}
+			label.TextStyle = fyne.TextStyle{}
+			summary := summaries[id.Row-1]
+			switch id.Col {
+			case 0:
+				label.SetText(fmt.Sprintf("%d-%02d", summary.Year, summary.Month))
+			case 1:
+				label.SetText(formatDuration(summary.Work))
+			case 2:
+				label.SetText(formatDuration(summary.Break))
+			case 3:
+				label.SetText(fmt.Sprint(summary.WorkingDays))
+			default:
+				label.SetText(formatDuration(summary.Surcharges[id.Col-len(monthlyColumns)]))
+			}
+		},
+	)
+	for col := range columns {
+		table.SetColumnWidth(col, 100)
+	}
+	for col := len(monthlyColumns); col < len(columns); col++ {
+		table.SetColumnWidth(col, 130)
+	}
+
+	window.SetContent(container.NewStack(table))
+	width := monthlyWindowWidth + 130*float32(len(columns)-len(monthlyColumns))
+	window.Resize(fyne.NewSize(width, monthlyWindowHeight))
+	return window
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A4 page size in PDF points (1/72 inch)
+const (
+	pdfPageWidth  = 595.28
+	pdfPageHeight = 841.89
+)
+
+// pdfDocument is a minimal PDF 1.4 writer for text and lines using the standard
+// Helvetica fonts, so no fonts need to be embedded. It writes no timestamps or IDs,
+// the same content always gives the same bytes.
+type pdfDocument struct {
+	pages []*bytes.Buffer
+}
+
+// AddPage starts a new A4 page; following drawing calls go to it
+func (d *pdfDocument) AddPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+}
+
+func (d *pdfDocument) page() *bytes.Buffer {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+	return d.pages[len(d.pages)-1]
+}
+
+// Text draws text with its baseline at y, measured from the top of the page
+func (d *pdfDocument) Text(x, y, size float64, bold bool, text string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(d.page(), "BT /%s %s Tf %s %s Td (%s) Tj ET\n",
+		font, pdfNumber(size), pdfNumber(x), pdfNumber(pdfPageHeight-y), pdfEscape(text))
+}
+
+// TextRight draws text ending at x, using the Helvetica character widths
+func (d *pdfDocument) TextRight(x, y, size float64, bold bool, text string) {
+	d.Text(x-pdfTextWidth(text, size), y, size, bold, text)
+}
+
+// Line draws a line from (x1, y1) to (x2, y2), measured from the top of the page
+func (d *pdfDocument) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(d.page(), "%s w %s %s m %s %s l S\n", pdfNumber(width),
+		pdfNumber(x1), pdfNumber(pdfPageHeight-y1), pdfNumber(x2), pdfNumber(pdfPageHeight-y2))
+}
+
+// WriteTo writes the document with a cross-reference table
+func (d *pdfDocument) WriteTo(w io.Writer) (int64, error) {
+	d.page() // An empty document still needs one page
+
+	// Objects: 1 catalog, 2 page tree, 3-4 fonts, then a page and its content stream per page
+	var objects []string
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	objects = append(objects,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+	)
+	for i, content := range d.pages {
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+				pdfNumber(pdfPageWidth), pdfNumber(pdfPageHeight), 6+2*i),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+		)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	n, err := w.Write(buf.Bytes())
+	return int64(n), err
+}
+
+// pdfNumber formats a coordinate without trailing zeros
+func pdfNumber(v float64) string {
+	s := fmt.Sprintf("%.2f", v)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// pdfEscape converts text to a WinAnsi string literal body; characters outside Latin-1 become '?'
+func pdfEscape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// pdfTextWidth approximates the width of Helvetica text: digits and most
+// characters are 0.556 em wide, which is exact for the numbers and times we align
+func pdfTextWidth(text string, size float64) float64 {
+	var width float64
+	for _, r := range text {
+		switch r {
+		case ' ', ':', '.', ',':
+			width += 0.278
+		case '-':
+			width += 0.333
+		case '+':
+			width += 0.584
+		default:
+			width += 0.556
+		}
+	}
+	return width * size
+}
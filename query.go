@@ -0,0 +1,357 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Query is a compiled filter expression over session fields, e.g.
+//
+//	work > 6h and break = 0 and weekday = fri and quarter = 2
+//
+// Comparisons are combined with "and", "or", "not" and parentheses.
+type Query struct {
+	source string
+	match  func(Session) bool
+}
+
+// queryFields lists the fields a query can compare, with a short description for help output
+var queryFields = []struct{ name, help string }{
+	{"date", "session date, e.g. date >= 2025-04-01"},
+	{"year", "year of the session date"},
+	{"month", "month of the session date (1-12)"},
+	{"quarter", "quarter of the session date (1-4)"},
+	{"weekday", "mon ... sun"},
+	{"start", "clock time the session started, e.g. start < 08:00"},
+	{"end", "clock time the session ended"},
+	{"duration", "total session length, e.g. duration > 6h"},
+	{"break", "break time, e.g. break < 30m"},
+	{"work", "duration minus break"},
+	{"project", "project name (~ matches a substring)"},
+	{"note", "description text (~ matches a substring)"},
+}
+
+// ParseQuery compiles a filter expression. An empty expression matches every session.
+func ParseQuery(source string) (*Query, error) {
+	tokens, err := tokenizeQuery(source)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return &Query{source: source, match: func(Session) bool { return true }}, nil
+	}
+
+	p := &queryParser{tokens: tokens}
+	match, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos])
+	}
+	return &Query{source: source, match: match}, nil
+}
+
+// Match reports whether a session satisfies the query
+func (q *Query) Match(session Session) bool {
+	return q.match(session)
+}
+
+func (q *Query) String() string {
+	return q.source
+}
+
+// QuerySessions streams the stored sessions matching q to fn until fn returns false,
+// with times in the configured report zone, so start and end compare to the shown clock
+func (s *Storage) QuerySessions(q *Query, fn func(session Session) bool) error {
+	config, err := s.LoadConfig()
+	if err != nil {
+		return err
+	}
+	loc := config.ReportLocation()
+
+	return s.EachSession(func(session Session) bool {
+		if loc != nil {
+			session = session.In(loc)
+		}
+		if !q.Match(session) {
+			return true
+		}
+		return fn(session)
+	})
+}
+
+// tokenizeQuery splits a query into words, quoted strings, operators and parentheses
+func tokenizeQuery(source string) ([]string, error) {
+	var tokens []string
+	runes := []rune(source)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')':
+			tokens = append(tokens, string(r))
+			i++
+		case r == '"' || r == '\'':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+			if end == len(runes) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			// Keep the opening quote so the parser can tell literals from keywords
+			tokens = append(tokens, string(runes[i:end]))
+			i = end + 1
+		case strings.ContainsRune("<>=!~", r):
+			end := i + 1
+			if end < len(runes) && runes[end] == '=' {
+				end++
+			}
+			tokens = append(tokens, string(runes[i:end]))
+			i = end
+		default:
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) && !strings.ContainsRune("()<>=!~\"'", runes[end]) {
+				end++
+			}
+			tokens = append(tokens, string(runes[i:end]))
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+type queryParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *queryParser) peekKeyword(keyword string) bool {
+	return p.pos < len(p.tokens) && strings.EqualFold(p.tokens[p.pos], keyword)
+}
+
+func (p *queryParser) next() (string, error) {
+	if p.pos >= len(p.tokens) {
+		return "", fmt.Errorf("unexpected end of query")
+	}
+	token := p.tokens[p.pos]
+	p.pos++
+	return token, nil
+}
+
+func (p *queryParser) parseOr() (func(Session) bool, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("or") {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(s Session) bool { return l(s) || right(s) }
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseAnd() (func(Session) bool, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("and") {
+		p.pos++
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(s Session) bool { return l(s) && right(s) }
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseNot() (func(Session) bool, error) {
+	if p.peekKeyword("not") {
+		p.pos++
+		inner, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return func(s Session) bool { return !inner(s) }, nil
+	}
+	if p.peekKeyword("(") {
+		p.pos++
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if token, err := p.next(); err != nil || token != ")" {
+			return nil, fmt.Errorf("missing \")\"")
+		}
+		return inner, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *queryParser) parseComparison() (func(Session) bool, error) {
+	field, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	op, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	value, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	field = strings.ToLower(field)
+	if strings.HasPrefix(value, `"`) || strings.HasPrefix(value, "'") {
+		value = value[1:]
+	}
+
+	switch field {
+	case "date":
+		if _, err := time.Parse("2006-01-02", value); err != nil {
+			return nil, fmt.Errorf("invalid date %q", value)
+		}
+		return compareStrings(op, value, func(s Session) string { return s.Date })
+	case "year", "month", "quarter":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q", field, value)
+		}
+		return compareInts(op, n, func(s Session) (int, bool) {
+			date, err := time.Parse("2006-01-02", s.Date)
+			if err != nil {
+				return 0, false
+			}
+			switch field {
+			case "year":
+				return date.Year(), true
+			case "month":
+				return int(date.Month()), true
+			default:
+				return (int(date.Month())-1)/3 + 1, true
+			}
+		})
+	case "weekday":
+		weekday, err := parseWeekday(value)
+		if err != nil {
+			return nil, err
+		}
+		return compareInts(op, int(weekday), func(s Session) (int, bool) {
+			date, err := time.Parse("2006-01-02", s.Date)
+			return int(date.Weekday()), err == nil
+		})
+	case "start", "end":
+		clock, err := time.Parse("15:04", value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid clock time %q", value)
+		}
+		minutes := clock.Hour()*60 + clock.Minute()
+		return compareInts(op, minutes, func(s Session) (int, bool) {
+			t := s.Start
+			if field == "end" {
+				t = s.End
+			}
+			return t.Hour()*60 + t.Minute(), !t.IsZero()
+		})
+	case "duration", "break", "work":
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration %q", value)
+		}
+		return compareInts(op, int(d.Seconds()), func(s Session) (int, bool) {
+			switch field {
+			case "duration":
+				return int(s.Duration), true
+			case "break":
+				return int(s.BreakTime), true
+			default:
+				return int(s.Duration - s.BreakTime), true
+			}
+		})
+	case "project":
+		return compareText(op, value, func(s Session) string { return s.Project })
+	case "note", "description":
+		return compareText(op, value, func(s Session) string { return s.Description })
+	}
+	return nil, fmt.Errorf("unknown field %q", field)
+}
+
+func compareInts(op string, want int, get func(Session) (int, bool)) (func(Session) bool, error) {
+	var cmp func(a, b int) bool
+	switch op {
+	case "=":
+		cmp = func(a, b int) bool { return a == b }
+	case "!=":
+		cmp = func(a, b int) bool { return a != b }
+	case "<":
+		cmp = func(a, b int) bool { return a < b }
+	case "<=":
+		cmp = func(a, b int) bool { return a <= b }
+	case ">":
+		cmp = func(a, b int) bool { return a > b }
+	case ">=":
+		cmp = func(a, b int) bool { return a >= b }
+	default:
+		return nil, fmt.Errorf("operator %q cannot compare numbers", op)
+	}
+	return func(s Session) bool {
+		got, ok := get(s)
+		return ok && cmp(got, want)
+	}, nil
+}
+
+func compareStrings(op string, want string, get func(Session) string) (func(Session) bool, error) {
+	switch op {
+	case "=":
+		return func(s Session) bool { return get(s) == want }, nil
+	case "!=":
+		return func(s Session) bool { return get(s) != want }, nil
+	case "<":
+		return func(s Session) bool { return get(s) < want }, nil
+	case "<=":
+		return func(s Session) bool { return get(s) <= want }, nil
+	case ">":
+		return func(s Session) bool { return get(s) > want }, nil
+	case ">=":
+		return func(s Session) bool { return get(s) >= want }, nil
+	}
+	return nil, fmt.Errorf("operator %q cannot compare dates", op)
+}
+
+func compareText(op string, want string, get func(Session) string) (func(Session) bool, error) {
+	want = strings.ToLower(want)
+	switch op {
+	case "=":
+		return func(s Session) bool { return strings.ToLower(get(s)) == want }, nil
+	case "!=":
+		return func(s Session) bool { return strings.ToLower(get(s)) != want }, nil
+	case "~":
+		return func(s Session) bool { return strings.Contains(strings.ToLower(get(s)), want) }, nil
+	}
+	return nil, fmt.Errorf("operator %q cannot compare text", op)
+}
+
+// parseWeekday parses English weekday names and their three-letter abbreviations
+func parseWeekday(value string) (time.Weekday, error) {
+	value = strings.ToLower(value)
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		name := strings.ToLower(day.String())
+		if value == name || value == name[:3] {
+			return day, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid weekday %q", value)
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQueryMatching(t *testing.T) {
+	friday := Session{
+		Date:        "2025-05-16", // Friday in Q2
+		Duration:    7 * 3600,
+		Start:       time.Date(2025, 5, 16, 7, 30, 0, 0, time.Local),
+		Project:     "Website",
+		Description: "Release preparation",
+	}
+	monday := Session{
+		Date:      "2025-01-13",
+		Duration:  8 * 3600,
+		BreakTime: 1800,
+		Project:   "Backend",
+	}
+
+	tests := []struct {
+		query  string
+		friday bool
+		monday bool
+	}{
+		{"", true, true},
+		{"work > 6h and break = 0 and weekday = fri and quarter = 2", true, false},
+		{"date >= 2025-04-01 and date <= 2025-06-30", true, false},
+		{"weekday = monday or project = website", true, true},
+		{"not (project = website)", false, true},
+		{"note ~ release", true, false},
+		{`project = "Backend"`, false, true},
+		{"start < 08:00", true, false}, // sessions without start times never match clock comparisons
+		{"break >= 30m and month = 1 and year = 2025", false, true},
+	}
+
+	for _, tt := range tests {
+		query, err := ParseQuery(tt.query)
+		if err != nil {
+			t.Errorf("ParseQuery(%q) failed: %v", tt.query, err)
+			continue
+		}
+		if got := query.Match(friday); got != tt.friday {
+			t.Errorf("%q on Friday session: got %v, want %v", tt.query, got, tt.friday)
+		}
+		if got := query.Match(monday); got != tt.monday {
+			t.Errorf("%q on Monday session: got %v, want %v", tt.query, got, tt.monday)
+		}
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	for _, query := range []string{
+		"work >",
+		"unknown = 1",
+		"weekday = someday",
+		"project > abc",
+		"(work > 1h",
+		"work > 1h extra",
+		`note ~ "open`,
+	} {
+		if _, err := ParseQuery(query); err == nil {
+			t.Errorf("ParseQuery(%q) should fail", query)
+		}
+	}
+}
+
+func TestQuerySessionsStreams(t *testing.T) {
+	storage := newTestStorage(t)
+	sessions := []Session{
+		{Date: "2025-05-12", Duration: 3600},
+		{Date: "2025-05-13", Duration: 7200},
+		{Date: "2025-05-14", Duration: 10800},
+	}
+	if err := storage.appendSessionsToCSV(sessions); err != nil {
+		t.Fatal(err)
+	}
+
+	query, _ := ParseQuery("duration >= 2h")
+	var dates []string
+	err := storage.QuerySessions(query, func(session Session) bool {
+		dates = append(dates, session.Date)
+		return false // stop after the first match
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dates) != 1 || dates[0] != "2025-05-13" {
+		t.Errorf("Expected to stop after first match 2025-05-13, got %v", dates)
+	}
+}
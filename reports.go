@@ -0,0 +1,292 @@
+package main
+
+import (
+	"sort"
+	"time"
+)
+
+// MonthlySummary aggregates the stored sessions of one calendar month
+type MonthlySummary struct {
+	Year        int
+	Month       time.Month
+	Work        time.Duration
+	Break       time.Duration
+	WorkingDays int
+	// Surcharges holds the worked time per configured surcharge rule, in the order of the rules
+	Surcharges []time.Duration
+}
+
+// MonthlySummaries returns one summary per month with stored sessions, newest first
+func (s *Storage) MonthlySummaries() ([]MonthlySummary, error) {
+	type monthKey struct {
+		year  int
+		month time.Month
+	}
+	summaries := make(map[monthKey]*MonthlySummary)
+	days := make(map[string]bool)
+	config, err := s.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	classifier := newSurchargeClassifier(config)
+
+	err = s.EachSession(func(session Session) bool {
+		date, err := time.Parse("2006-01-02", session.Date)
+		if err != nil {
+			return true // Skip invalid dates
+		}
+		key := monthKey{date.Year(), date.Month()}
+		summary, ok := summaries[key]
+		if !ok {
+			summary = &MonthlySummary{Year: key.year, Month: key.month, Surcharges: make([]time.Duration, len(config.Surcharges))}
+			summaries[key] = summary
+		}
+		if worked, ok := classifier.Classify(session); ok {
+			for i := range worked {
+				summary.Surcharges[i] += worked[i]
+			}
+		}
+
+		if work := session.Duration - session.BreakTime; work > 0 {
+			summary.Work += time.Duration(work) * time.Second
+			if !days[session.Date] {
+				days[session.Date] = true
+				summary.WorkingDays++
+			}
+		}
+		summary.Break += time.Duration(session.BreakTime) * time.Second
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]MonthlySummary, 0, len(summaries))
+	for _, summary := range summaries {
+		result = append(result, *summary)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Year != result[j].Year {
+			return result[i].Year > result[j].Year
+		}
+		return result[i].Month > result[j].Month
+	})
+	return result, nil
+}
+
+// DaySummary is the worked time and target of one calendar day
+type DaySummary struct {
+	Date     time.Time
+	Worked   time.Duration
+	Break    time.Duration
+	Target   time.Duration
+	Sessions []Session
+	// Holiday is the name of the public holiday on this day, if any
+	Holiday string
+	// Absence is the vacation, sick or other absence on this day, if any
+	Absence *Absence
+}
+
+// Note returns the holiday or absence of the day for display, empty if there is none
+func (d DaySummary) Note() string {
+	switch {
+	case d.Holiday != "":
+		return d.Holiday
+	case d.Absence != nil:
+		return d.Absence.Label()
+	}
+	return ""
+}
+
+// FirstStart returns the earliest session start of the day, zero if unknown
+func (d DaySummary) FirstStart() time.Time {
+	var first time.Time
+	for _, session := range d.Sessions {
+		if !session.Start.IsZero() && (first.IsZero() || session.Start.Before(first)) {
+			first = session.Start
+		}
+	}
+	return first
+}
+
+// LastEnd returns the latest session end of the day, zero if unknown
+func (d DaySummary) LastEnd() time.Time {
+	var last time.Time
+	for _, session := range d.Sessions {
+		if session.End.After(last) {
+			last = session.End
+		}
+	}
+	return last
+}
+
+// DailySummaries returns one summary per calendar day from from to to, including days without sessions
+func (s *Storage) DailySummaries(from, to time.Time) ([]DaySummary, error) {
+	config, err := s.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := s.LoadSessions(from, to)
+	if err != nil {
+		return nil, err
+	}
+	absences, err := s.LoadAbsences()
+	if err != nil {
+		return nil, err
+	}
+	byDate := make(map[string][]Session)
+	for _, session := range sessions {
+		byDate[session.Date] = append(byDate[session.Date], session)
+	}
+
+	var days []DaySummary
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		summary := DaySummary{Date: day, Target: absences.Target(config, day), Sessions: byDate[date]}
+		summary.Holiday, _ = config.HolidayOn(day)
+		if absence, ok := absences[date]; ok {
+			summary.Absence = &absence
+		}
+		for _, session := range summary.Sessions {
+			if work := session.Duration - session.BreakTime; work > 0 {
+				summary.Worked += time.Duration(work) * time.Second
+			}
+			summary.Break += time.Duration(session.BreakTime) * time.Second
+		}
+		days = append(days, summary)
+	}
+	return days, nil
+}
+
+// WeeklySummary aggregates the worked time, breaks and target of one week
+type WeeklySummary struct {
+	Year   int // year of the week as returned by Config.WeekOf, may differ from Start's year
+	Week   int
+	Start  time.Time
+	Worked time.Duration
+	Break  time.Duration
+	Target time.Duration
+}
+
+// WeeklySummaries returns the last n weeks up to and including the week containing today, oldest first
+func (s *Storage) WeeklySummaries(n int, today time.Time) ([]WeeklySummary, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	config, err := s.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	first := startOfWeek(today, config.FirstWeekday()).AddDate(0, 0, -7*(n-1))
+	days, err := s.DailySummaries(first, first.AddDate(0, 0, 7*n-1))
+	if err != nil {
+		return nil, err
+	}
+
+	weeks := make([]WeeklySummary, n)
+	for i := range weeks {
+		weeks[i].Start = first.AddDate(0, 0, 7*i)
+		weeks[i].Year, weeks[i].Week = config.WeekOf(weeks[i].Start)
+	}
+	for i, day := range days {
+		week := &weeks[i/7]
+		week.Worked += day.Worked
+		week.Break += day.Break
+		week.Target += day.Target
+	}
+	return weeks, nil
+}
+
+// ProjectTotal is the worked time booked on one project
+type ProjectTotal struct {
+	Project string
+	Worked  time.Duration
+}
+
+// PeriodReport summarizes the stored sessions of a date range for exported reports
+type PeriodReport struct {
+	From        time.Time
+	To          time.Time
+	Days        []DaySummary
+	Projects    []ProjectTotal // largest first
+	Worked      time.Duration
+	Break       time.Duration
+	Target      time.Duration
+	WorkingDays int
+	// Surcharges holds the worked time per configured surcharge rule
+	Surcharges []SurchargeBucket
+	// Unclassified is the worked time of sessions without start and end times,
+	// which can't be sorted into surcharge buckets
+	Unclassified time.Duration
+}
+
+// Delta returns the overtime (positive) or undertime (negative) of the period
+func (r *PeriodReport) Delta() time.Duration {
+	return r.Worked - r.Target
+}
+
+// PeriodReport builds a report from from to to; a zero from starts at the first
+// stored session and a zero to ends on the current working day
+func (s *Storage) PeriodReport(from, to time.Time) (*PeriodReport, error) {
+	if to.IsZero() {
+		today, err := s.Today()
+		if err != nil {
+			return nil, err
+		}
+		to = today
+	}
+	if from.IsZero() {
+		from = to
+		err := s.EachSession(func(session Session) bool {
+			date, err := time.ParseInLocation("2006-01-02", session.Date, time.Local)
+			if err == nil && date.Before(from) {
+				from = date
+			}
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	days, err := s.DailySummaries(from, to)
+	if err != nil {
+		return nil, err
+	}
+	config, err := s.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	report := &PeriodReport{From: from, To: to, Days: days}
+	projects := make(map[string]time.Duration)
+	var sessions []Session
+	for _, day := range days {
+		sessions = append(sessions, day.Sessions...)
+		report.Worked += day.Worked
+		report.Break += day.Break
+		report.Target += day.Target
+		if day.Worked > 0 {
+			report.WorkingDays++
+		}
+		for _, session := range day.Sessions {
+			if work := session.Duration - session.BreakTime; work > 0 {
+				projects[session.Project] += time.Duration(work) * time.Second
+			}
+		}
+	}
+
+	if len(config.Surcharges) > 0 {
+		report.Surcharges, report.Unclassified = classifySurcharges(config, sessions)
+	}
+
+	for project, worked := range projects {
+		report.Projects = append(report.Projects, ProjectTotal{Project: project, Worked: worked})
+	}
+	sort.Slice(report.Projects, func(i, j int) bool {
+		if report.Projects[i].Worked != report.Projects[j].Worked {
+			return report.Projects[i].Worked > report.Projects[j].Worked
+		}
+		return report.Projects[i].Project < report.Projects[j].Project
+	})
+	return report, nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMonthlySummaries(t *testing.T) {
+	storage := newTestStorage(t)
+	sessions := []Session{
+		{Date: "2025-01-30", Duration: 8 * 3600, BreakTime: 1800},
+		{Date: "2025-01-30", Duration: 3600},
+		{Date: "2025-01-31", Duration: 4 * 3600},
+		{Date: "2025-02-03", Duration: 2 * 3600, BreakTime: 600},
+	}
+	if err := storage.appendSessionsToCSV(sessions); err != nil {
+		t.Fatal(err)
+	}
+
+	summaries, err := storage.MonthlySummaries()
+	if err != nil {
+		t.Fatalf("MonthlySummaries failed: %v", err)
+	}
+	if len(summaries) != 2 {
+		t.Fatalf("Expected 2 months, got %d", len(summaries))
+	}
+
+	february, january := summaries[0], summaries[1]
+	if february.Month != time.February || february.Work != 2*time.Hour-10*time.Minute || february.WorkingDays != 1 {
+		t.Errorf("Unexpected February summary: %+v", february)
+	}
+	if january.Month != time.January || january.Work != 12*time.Hour+30*time.Minute || january.Break != 30*time.Minute || january.WorkingDays != 2 {
+		t.Errorf("Unexpected January summary: %+v", january)
+	}
+}
+
+func TestDailySummaries(t *testing.T) {
+	storage := newTestStorage(t)
+	sessions := []Session{
+		{Date: "2025-03-07", Duration: 4 * 3600},
+		{Date: "2025-03-07", Duration: 3 * 3600, BreakTime: 1800},
+		{Date: "2025-03-09", Duration: 3600},
+	}
+	if err := storage.appendSessionsToCSV(sessions); err != nil {
+		t.Fatal(err)
+	}
+
+	from := time.Date(2025, 3, 7, 0, 0, 0, 0, time.Local)
+	days, err := storage.DailySummaries(from, from.AddDate(0, 0, 3))
+	if err != nil {
+		t.Fatalf("DailySummaries failed: %v", err)
+	}
+	if len(days) != 4 {
+		t.Fatalf("Expected 4 days including empty ones, got %d", len(days))
+	}
+	friday, saturday, sunday := days[0], days[1], days[2]
+	if friday.Worked != 6*time.Hour+30*time.Minute || friday.Target != 8*time.Hour || len(friday.Sessions) != 2 {
+		t.Errorf("Unexpected Friday: %+v", friday)
+	}
+	if saturday.Worked != 0 || saturday.Target != 0 || len(saturday.Sessions) != 0 {
+		t.Errorf("Unexpected Saturday: %+v", saturday)
+	}
+	if sunday.Worked != time.Hour {
+		t.Errorf("Unexpected Sunday: %+v", sunday)
+	}
+}
+
+func TestWeeklySummariesAcrossISOYear(t *testing.T) {
+	storage := newTestStorage(t)
+	sessions := []Session{
+		// Monday 2024-12-30 and Friday 2025-01-03 both belong to ISO week 2025-W01
+		{Date: "2024-12-30", Duration: 8 * 3600, BreakTime: 1800},
+		{Date: "2025-01-03", Duration: 4 * 3600},
+		{Date: "2024-12-27", Duration: 2 * 3600},
+	}
+	if err := storage.appendSessionsToCSV(sessions); err != nil {
+		t.Fatal(err)
+	}
+
+	weeks, err := storage.WeeklySummaries(2, time.Date(2025, 1, 4, 12, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatalf("WeeklySummaries failed: %v", err)
+	}
+	if len(weeks) != 2 {
+		t.Fatalf("Expected 2 weeks, got %d", len(weeks))
+	}
+	previous, current := weeks[0], weeks[1]
+	if previous.Year != 2024 || previous.Week != 52 || previous.Worked != 2*time.Hour {
+		t.Errorf("Unexpected previous week: %+v", previous)
+	}
+	if current.Year != 2025 || current.Week != 1 || current.Worked != 11*time.Hour+30*time.Minute || current.Break != 30*time.Minute {
+		t.Errorf("Unexpected current week: %+v", current)
+	}
+	if current.Target != 40*time.Hour {
+		t.Errorf("Expected a 40h target, got %v", current.Target)
+	}
+}
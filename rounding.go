@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	roundPerSession = "session"
+	roundPerDay     = "day"
+
+	roundUp      = "up"
+	roundDown    = "down"
+	roundNearest = "nearest"
+)
+
+// RoundingPolicy rounds the billed time of invoices. It only applies to billing; sessions
+// and all other reports keep the exact recorded time.
+type RoundingPolicy struct {
+	// Per is the time that is rounded: each "session" (default) or the sum of a project's "day"
+	Per string `json:"per,omitempty"`
+	// Mode is "up", "down" or "nearest" (default)
+	Mode string `json:"mode,omitempty"`
+	// IncrementMinutes is the billing increment, e.g. 6 or 15; 0 doesn't round
+	IncrementMinutes int `json:"increment_minutes,omitempty"`
+	// MinimumMinutes is the least time billed per session or day
+	MinimumMinutes int `json:"minimum_minutes,omitempty"`
+}
+
+// validate checks the policy; a nil policy bills the exact time
+func (p *RoundingPolicy) validate() error {
+	if p == nil {
+		return nil
+	}
+	switch p.Per {
+	case "", roundPerSession, roundPerDay:
+	default:
+		return fmt.Errorf("per must be %q or %q, got %q", roundPerSession, roundPerDay, p.Per)
+	}
+	switch p.Mode {
+	case "", roundUp, roundDown, roundNearest:
+	default:
+		return fmt.Errorf("mode must be %q, %q or %q, got %q", roundUp, roundDown, roundNearest, p.Mode)
+	}
+	if p.IncrementMinutes < 0 || p.IncrementMinutes > 60 {
+		return fmt.Errorf("increment_minutes must be between 0 and 60")
+	}
+	if p.MinimumMinutes < 0 {
+		return fmt.Errorf("minimum_minutes must not be negative")
+	}
+	return nil
+}
+
+// perDay reports whether the work of a day is rounded instead of each session
+func (p *RoundingPolicy) perDay() bool {
+	return p != nil && p.Per == roundPerDay
+}
+
+// apply rounds the worked time of a session or day to the increment and raises it to the minimum
+func (p *RoundingPolicy) apply(work time.Duration) time.Duration {
+	if p == nil {
+		return work
+	}
+	if increment := time.Duration(p.IncrementMinutes) * time.Minute; increment > 0 {
+		rounded := work.Truncate(increment)
+		switch p.Mode {
+		case roundUp:
+			if rounded < work {
+				rounded += increment
+			}
+		case roundDown:
+		default:
+			rounded = work.Round(increment)
+		}
+		work = rounded
+	}
+	if minimum := time.Duration(p.MinimumMinutes) * time.Minute; work < minimum {
+		work = minimum
+	}
+	return work
+}
+
+// String describes the policy for invoices, e.g. "Billed in 15-minute increments per
+// session, rounded up, at least 30 minutes per session."
+func (p *RoundingPolicy) String() string {
+	if p == nil || (p.IncrementMinutes == 0 && p.MinimumMinutes == 0) {
+		return ""
+	}
+	per, mode := p.Per, p.Mode
+	if per == "" {
+		per = roundPerSession
+	}
+	if mode == "" {
+		mode = roundNearest
+	}
+
+	var parts []string
+	if p.IncrementMinutes > 0 {
+		rounded := "rounded " + mode
+		if mode == roundNearest {
+			rounded = "rounded to the nearest increment"
+		}
+		parts = append(parts, fmt.Sprintf("Billed in %d-minute increments per %s, %s", p.IncrementMinutes, per, rounded))
+	}
+	if p.MinimumMinutes > 0 {
+		minimum := fmt.Sprintf("at least %d minutes per %s", p.MinimumMinutes, per)
+		if len(parts) == 0 {
+			minimum = "Billed " + minimum
+		}
+		parts = append(parts, minimum)
+	}
+	return strings.Join(parts, ", ") + "."
+}
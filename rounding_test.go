@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRoundingPolicy(t *testing.T) {
+	tests := []struct {
+		policy   *RoundingPolicy
+		work     time.Duration
+		expected time.Duration
+	}{
+		{nil, 7 * time.Minute, 7 * time.Minute},
+		{&RoundingPolicy{IncrementMinutes: 15}, 7 * time.Minute, 0},
+		{&RoundingPolicy{IncrementMinutes: 15}, 8 * time.Minute, 15 * time.Minute},
+		{&RoundingPolicy{IncrementMinutes: 15, Mode: roundUp}, 61 * time.Minute, 75 * time.Minute},
+		{&RoundingPolicy{IncrementMinutes: 15, Mode: roundUp}, 60 * time.Minute, 60 * time.Minute},
+		{&RoundingPolicy{IncrementMinutes: 6, Mode: roundDown}, 11*time.Minute + 59*time.Second, 6 * time.Minute},
+		{&RoundingPolicy{IncrementMinutes: 6, Mode: roundUp, MinimumMinutes: 30}, 10 * time.Minute, 30 * time.Minute},
+		{&RoundingPolicy{MinimumMinutes: 30}, 40 * time.Minute, 40 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := tt.policy.apply(tt.work); got != tt.expected {
+			t.Errorf("%+v: expected %v for %v, got %v", tt.policy, tt.expected, tt.work, got)
+		}
+	}
+
+	for _, policy := range []*RoundingPolicy{{Per: "week"}, {Mode: "ceil"}, {IncrementMinutes: 90}, {MinimumMinutes: -1}} {
+		billing := &BillingConfig{Rounding: policy, Clients: map[string]BillingClient{"a": {Rate: 1, Projects: map[string]float64{"x": 0}}}}
+		if err := (&Config{Billing: billing}).Validate(); err == nil {
+			t.Errorf("Expected %+v to fail validation", policy)
+		}
+	}
+
+	policy := &RoundingPolicy{IncrementMinutes: 15, Mode: roundUp, MinimumMinutes: 30}
+	if got := policy.String(); got != "Billed in 15-minute increments per session, rounded up, at least 30 minutes per session." {
+		t.Errorf("Unexpected description %q", got)
+	}
+}
+
+func TestInvoiceRounding(t *testing.T) {
+	storage := newTestStorage(t)
+	config := &Config{Billing: &BillingConfig{
+		Rounding: &RoundingPolicy{IncrementMinutes: 15, Mode: roundUp},
+		Clients: map[string]BillingClient{
+			"acme": {Rate: 100, Projects: map[string]float64{"Website": 0}},
+			"beta": {Rate: 100, Rounding: &RoundingPolicy{Per: roundPerDay, IncrementMinutes: 6, MinimumMinutes: 60}, Projects: map[string]float64{"App": 0}},
+		},
+	}}
+	if err := storage.SaveConfig(config); err != nil {
+		t.Fatal(err)
+	}
+	sessions := []Session{
+		{Date: "2025-03-10", Duration: 20 * 60, Project: "Website"},
+		{Date: "2025-03-10", Duration: 20 * 60, Project: "Website"},
+		{Date: "2025-03-10", Duration: 20 * 60, Project: "App"},
+		{Date: "2025-03-10", Duration: 20 * 60, Project: "App"},
+		{Date: "2025-03-11", Duration: 62 * 60, Project: "App"},
+	}
+	if err := storage.appendSessionsToCSV(sessions); err != nil {
+		t.Fatal(err)
+	}
+	before, err := os.ReadFile(storage.csvFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.Local)
+	to := time.Date(2025, 3, 31, 0, 0, 0, 0, time.Local)
+	// Per session: each 20 minutes are billed as 30
+	invoice, err := storage.PrepareInvoice("acme", from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if line := invoice.Lines[0]; line.Hours != time.Hour || line.Recorded != 40*time.Minute || invoice.Net != 10000 {
+		t.Errorf("Expected 1:00 billed of 0:40 recorded for 100.00, got %+v and %d", line, invoice.Net)
+	}
+
+	// Per day: 40 minutes are raised to the 1h minimum, 62 minutes are rounded to 60
+	invoice, err = storage.PrepareInvoice("beta", from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if billed, recorded := invoice.BilledHours(); billed != 2*time.Hour || recorded != 102*time.Minute {
+		t.Errorf("Expected 2:00 billed of 1:42 recorded, got %v and %v", billed, recorded)
+	}
+	var buf bytes.Buffer
+	if err := WriteInvoiceMarkdown(&buf, invoice); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "| App | 3 | 2.00 |") || !strings.Contains(buf.String(), "Billed in 6-minute increments per day") {
+		t.Errorf("Unexpected Markdown invoice:\n%s", buf.String())
+	}
+	if err := storage.RecordInvoice(invoice); err != nil {
+		t.Fatal(err)
+	}
+
+	// Billing never touches the recorded sessions or other reports
+	after, err := os.ReadFile(storage.csvFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(before, after) {
+		t.Error("Expected the sessions file to stay unmodified")
+	}
+	report, err := storage.PeriodReport(from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Worked != 142*time.Minute {
+		t.Errorf("Expected the exact 2:22 in reports, got %v", report.Worked)
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// statsDefaultDays is the period shown by the statistics window and CLI report by default
+const statsDefaultDays = 30
+
+// WorkStats describes working habits over a period
+type WorkStats struct {
+	From        time.Time
+	To          time.Time
+	WorkingDays int
+	Sessions    int
+	// AverageFirstStart is the average time of day of the first start, as time since midnight
+	AverageFirstStart time.Duration
+	// AverageDayLength is the average time from first start to last end
+	AverageDayLength time.Duration
+	AverageWorked    time.Duration
+	// BreakRatio is the share of breaks in worked plus break time
+	BreakRatio     float64
+	LongestSession Session
+	// Streaks count consecutive days with a target on which it was met; days without a target are skipped
+	CurrentStreak int
+	LongestStreak int
+}
+
+// SessionsPerDay returns the average number of sessions per working day
+func (s WorkStats) SessionsPerDay() float64 {
+	if s.WorkingDays == 0 {
+		return 0
+	}
+	return float64(s.Sessions) / float64(s.WorkingDays)
+}
+
+// Rows returns the statistics as label and value pairs for display
+func (s WorkStats) Rows() [][2]string {
+	longest := "-"
+	if s.LongestSession.Duration > 0 {
+		longest = fmt.Sprintf("%s on %s", formatDuration(time.Duration(s.LongestSession.Duration-s.LongestSession.BreakTime)*time.Second), s.LongestSession.Date)
+	}
+	return [][2]string{
+		{"Period", fmt.Sprintf("%s to %s", s.From.Format("2006-01-02"), s.To.Format("2006-01-02"))},
+		{"Working days", fmt.Sprint(s.WorkingDays)},
+		{"Average first start", formatTimeOfDay(s.AverageFirstStart)},
+		{"Average day length", formatDuration(s.AverageDayLength)},
+		{"Average worked per day", formatDuration(s.AverageWorked)},
+		{"Break ratio", fmt.Sprintf("%.1f%%", s.BreakRatio*100)},
+		{"Longest session", longest},
+		{"Sessions per day", fmt.Sprintf("%.1f", s.SessionsPerDay())},
+		{"Current target streak", fmt.Sprintf("%d days", s.CurrentStreak)},
+		{"Longest target streak", fmt.Sprintf("%d days", s.LongestStreak)},
+	}
+}
+
+// computeWorkStats derives the statistics from consecutive days; today's target
+// doesn't end the current streak while the day is still in progress
+func computeWorkStats(days []DaySummary, today time.Time) WorkStats {
+	var stats WorkStats
+	if len(days) == 0 {
+		return stats
+	}
+	stats.From, stats.To = days[0].Date, days[len(days)-1].Date
+
+	var worked, breaks, firstStarts, spans time.Duration
+	var starts, spanDays, streak int
+	todayDate := today.Format("2006-01-02")
+	for _, day := range days {
+		stats.Sessions += len(day.Sessions)
+		for _, session := range day.Sessions {
+			if session.Duration-session.BreakTime > stats.LongestSession.Duration-stats.LongestSession.BreakTime {
+				stats.LongestSession = session
+			}
+		}
+		if day.Worked > 0 {
+			stats.WorkingDays++
+			worked += day.Worked
+			breaks += day.Break
+		}
+
+		if first := day.FirstStart(); !first.IsZero() {
+			firstStarts += time.Duration(first.Hour())*time.Hour + time.Duration(first.Minute())*time.Minute +
+				time.Duration(first.Second())*time.Second
+			starts++
+			if last := day.LastEnd(); last.After(first) {
+				spans += last.Sub(first)
+				spanDays++
+			}
+		}
+
+		if day.Target <= 0 {
+			continue
+		}
+		if day.Worked >= day.Target {
+			streak++
+			if streak > stats.LongestStreak {
+				stats.LongestStreak = streak
+			}
+		} else if day.Date.Format("2006-01-02") != todayDate {
+			streak = 0
+		}
+	}
+	stats.CurrentStreak = streak
+
+	if stats.WorkingDays > 0 {
+		stats.AverageWorked = worked / time.Duration(stats.WorkingDays)
+	}
+	if starts > 0 {
+		stats.AverageFirstStart = firstStarts / time.Duration(starts)
+	}
+	if spanDays > 0 {
+		stats.AverageDayLength = spans / time.Duration(spanDays)
+	}
+	if worked+breaks > 0 {
+		stats.BreakRatio = float64(breaks) / float64(worked+breaks)
+	}
+	return stats
+}
+
+// WorkStats computes the statistics of the stored sessions from from to to,
+// with the same open bounds as PeriodReport
+func (s *Storage) WorkStats(from, to time.Time) (WorkStats, error) {
+	report, err := s.PeriodReport(from, to)
+	if err != nil {
+		return WorkStats{}, err
+	}
+	today, err := s.Today()
+	if err != nil {
+		return WorkStats{}, err
+	}
+	return computeWorkStats(report.Days, today), nil
+}
+
+// formatTimeOfDay formats a time since midnight as HH:MM
+func formatTimeOfDay(d time.Duration) string {
+	d = d.Round(time.Minute)
+	return time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC).Add(d).Format("15:04")
+}
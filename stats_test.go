@@ -0,0 +1,55 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComputeWorkStats(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, 3, day, hour, minute, 0, 0, time.Local)
+	}
+	day := func(date int, worked, target time.Duration, sessions ...Session) DaySummary {
+		return DaySummary{Date: at(date, 0, 0), Worked: worked, Target: target, Sessions: sessions}
+	}
+	days := []DaySummary{
+		// Thursday and Friday meet the target, the weekend doesn't interrupt the streak
+		day(6, 8*time.Hour, 8*time.Hour, Session{Date: "2025-03-06", Start: at(6, 8, 0), End: at(6, 16, 30), Duration: 8*3600 + 1800, BreakTime: 1800}),
+		day(7, 9*time.Hour, 8*time.Hour,
+			Session{Date: "2025-03-07", Start: at(7, 9, 0), End: at(7, 13, 0), Duration: 4 * 3600},
+			Session{Date: "2025-03-07", Start: at(7, 13, 30), End: at(7, 18, 30), Duration: 5 * 3600}),
+		day(8, 0, 0),
+		day(9, 0, 0),
+		day(10, 8*time.Hour+30*time.Minute, 8*time.Hour, Session{Date: "2025-03-10", Start: at(10, 7, 0), End: at(10, 15, 30), Duration: 8*3600 + 1800}),
+		// Today is still in progress and doesn't break the streak
+		day(11, 2*time.Hour, 8*time.Hour, Session{Date: "2025-03-11", Start: at(11, 8, 0), End: at(11, 10, 0), Duration: 2 * 3600}),
+	}
+	days[0].Break = 30 * time.Minute
+
+	stats := computeWorkStats(days, at(11, 10, 0))
+	if stats.WorkingDays != 4 || stats.Sessions != 5 || stats.SessionsPerDay() != 1.25 {
+		t.Errorf("Unexpected counts: %+v", stats)
+	}
+	if stats.AverageFirstStart != 8*time.Hour {
+		t.Errorf("Expected average first start 08:00, got %s", formatTimeOfDay(stats.AverageFirstStart))
+	}
+	// Spans: 8:30, 9:30, 8:30, 2:00
+	if stats.AverageDayLength != 7*time.Hour+7*time.Minute+30*time.Second {
+		t.Errorf("Expected average day length 7:07:30, got %v", stats.AverageDayLength)
+	}
+	if stats.LongestSession.Date != "2025-03-10" {
+		t.Errorf("Expected the longest session on 2025-03-10, got %+v", stats.LongestSession)
+	}
+	if stats.CurrentStreak != 3 || stats.LongestStreak != 3 {
+		t.Errorf("Expected streaks of 3 days, got current %d and longest %d", stats.CurrentStreak, stats.LongestStreak)
+	}
+	if want := 0.5 / 28; stats.BreakRatio < want-1e-9 || stats.BreakRatio > want+1e-9 {
+		t.Errorf("Expected break ratio %f, got %f", want, stats.BreakRatio)
+	}
+
+	// A missed target on a past day resets the current streak
+	stats = computeWorkStats(days, at(12, 10, 0))
+	if stats.CurrentStreak != 0 || stats.LongestStreak != 3 {
+		t.Errorf("Expected the streak to end on a missed day, got current %d and longest %d", stats.CurrentStreak, stats.LongestStreak)
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	statsWindowTitle  = "Statistics"
+	statsWindowWidth  = 420
+	statsWindowHeight = 400
+)
+
+// NewStatsWindow creates a window with the work-pattern statistics of a selectable period
+func NewStatsWindow(app fyne.App, storage *Storage) fyne.Window {
+	window := app.NewWindow(statsWindowTitle)
+
+	today, err := storage.Today()
+	if err != nil {
+		today = time.Now()
+	}
+	fromEntry := widget.NewEntry()
+	fromEntry.SetText(today.AddDate(0, 0, 1-statsDefaultDays).Format("2006-01-02"))
+	toEntry := widget.NewEntry()
+	toEntry.SetText(today.Format("2006-01-02"))
+
+	grid := container.NewGridWithColumns(2)
+	status := widget.NewLabel("")
+	update := func() {
+		from, to, err := parseDateRange(fromEntry.Text, toEntry.Text)
+		if err != nil {
+			status.SetText("Error: " + err.Error())
+			return
+		}
+		stats, err := storage.WorkStats(from, to)
+		if err != nil {
+			status.SetText("Error: " + err.Error())
+			return
+		}
+		status.SetText("")
+		grid.Objects = nil
+		for _, row := range stats.Rows() {
+			grid.Add(widget.NewLabelWithStyle(row[0]+":", fyne.TextAlignTrailing, fyne.TextStyle{Bold: true}))
+			grid.Add(widget.NewLabel(row[1]))
+		}
+		grid.Refresh()
+	}
+	fromEntry.OnSubmitted = func(string) { update() }
+	toEntry.OnSubmitted = func(string) { update() }
+
+	period := widget.NewForm(widget.NewFormItem("From", fromEntry), widget.NewFormItem("To", toEntry))
+	window.SetContent(container.NewBorder(
+		container.NewVBox(period, widget.NewButton("Update", update)),
+		status,
+		nil, nil,
+		container.NewVScroll(grid),
+	))
+	update()
+	window.Resize(fyne.NewSize(statsWindowWidth, statsWindowHeight))
+	return window
+}
@@ -4,20 +4,32 @@ import (
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
+var csvHeader = []string{"date", "duration_s", "break_time_s", "start", "end", "project", "description", "breaks", "zone"}
+
 type Storage struct {
-	jsonFile string
-	csvFile  string
+	jsonFile     string
+	csvFile      string
+	configFile   string
+	absencesFile string
+	bookingsFile string
+	invoicesFile string
 }
 
 func NewStorage() *Storage {
 	return &Storage{
-		jsonFile: "current_session.json",
-		csvFile:  "sessions.csv",
+		jsonFile:     "current_session.json",
+		csvFile:      "sessions.csv",
+		configFile:   "config.json",
+		absencesFile: "absences.csv",
+		bookingsFile: "bookings.csv",
+		invoicesFile: "invoices.json",
 	}
 }
 
@@ -29,8 +41,8 @@ func (s *Storage) SaveTimer(timer *Timer) error {
 		}
 		// Clear sessions from timer after saving to CSV
 		timer.Sessions = []Session{}
-		// Update weekly total after saving to CSV
-		timer.updateWeeklyTotal()
+		// Update weekly and monthly totals after saving to CSV
+		timer.updateTotals()
 	}
 
 	// Then save current state to JSON
@@ -62,25 +74,66 @@ func (s *Storage) LoadTimer() (*Timer, error) {
 	// Initialize empty sessions slice
 	timer.Sessions = make([]Session, 0)
 
+	// Set storage and update weekly total; this also loads the day start used below
+	timer.SetStorage(s)
+
 	// Check if we need to handle day transition
-	now := time.Now()
-	if !timer.DayFirstStart.IsZero() && timer.DayFirstStart.Format("2006-01-02") != now.Format("2006-01-02") {
-		// Store yesterday's data
-		timer.YesterdayTotal = timer.DailyTotal
-		timer.YesterdayFirstStart = timer.DayFirstStart
+	timer.checkAndHandleDayTransition()
+
+	return &timer, nil
+}
 
-		// Reset today's tracking
-		timer.DayFirstStart = time.Time{}
-		timer.DailyTotal = 0
+// LoadSessions returns stored sessions whose date lies within [from, to], with times
+// in the configured report zone. A zero bound leaves that side of the range open.
+func (s *Storage) LoadSessions(from, to time.Time) ([]Session, error) {
+	config, err := s.LoadConfig()
+	if err != nil {
+		return nil, err
 	}
+	loc := config.ReportLocation()
 
-	// Set storage and update weekly total
-	timer.SetStorage(s)
+	var filtered []Session
+	err = s.EachSession(func(session Session) bool {
+		date, err := time.ParseInLocation("2006-01-02", session.Date, time.Local)
+		if err != nil {
+			return true // Skip invalid dates
+		}
+		if (from.IsZero() || !date.Before(from)) && (to.IsZero() || !date.After(to)) {
+			if loc != nil {
+				session = session.In(loc)
+			}
+			filtered = append(filtered, session)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return filtered, nil
+}
 
-	return &timer, nil
+// parseDateRange parses optional "2006-01-02" bounds; empty strings give zero times
+func parseDateRange(from, to string) (time.Time, time.Time, error) {
+	var start, end time.Time
+	var err error
+	if from != "" {
+		if start, err = time.ParseInLocation("2006-01-02", from, time.Local); err != nil {
+			return start, end, fmt.Errorf("invalid start date %q", from)
+		}
+	}
+	if to != "" {
+		if end, err = time.ParseInLocation("2006-01-02", to, time.Local); err != nil {
+			return start, end, fmt.Errorf("invalid end date %q", to)
+		}
+	}
+	return start, end, nil
 }
 
 func (s *Storage) appendSessionsToCSV(sessions []Session) error {
+	if err := s.upgradeCSVHeader(); err != nil {
+		return err
+	}
+
 	// Open file in append mode, create if not exists
 	file, err := os.OpenFile(s.csvFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
@@ -97,7 +150,7 @@ func (s *Storage) appendSessionsToCSV(sessions []Session) error {
 		return err
 	}
 	if stat.Size() == 0 {
-		if err := writer.Write([]string{"date", "duration_s", "break_time_s"}); err != nil {
+		if err := writer.Write(csvHeader); err != nil {
 			return err
 		}
 	}
@@ -108,6 +161,12 @@ func (s *Storage) appendSessionsToCSV(sessions []Session) error {
 			session.Date,
 			strconv.FormatInt(session.Duration, 10),
 			strconv.FormatInt(session.BreakTime, 10),
+			formatCSVTime(session.Start),
+			formatCSVTime(session.End),
+			session.Project,
+			session.Description,
+			formatCSVBreaks(session.Breaks),
+			session.Zone,
 		}
 		if err := writer.Write(record); err != nil {
 			return err
@@ -117,52 +176,163 @@ func (s *Storage) appendSessionsToCSV(sessions []Session) error {
 	return nil
 }
 
+// upgradeCSVHeader replaces the header of a sessions file written by an older
+// version, so that it names every column of the rows appended after it
+func (s *Storage) upgradeCSVHeader() error {
+	data, err := os.ReadFile(s.csvFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	line, rest, _ := strings.Cut(string(data), "\n")
+	header := strings.Join(csvHeader, ",")
+	line = strings.TrimSuffix(line, "\r")
+	if line == header || !strings.HasPrefix(line, csvHeader[0]+",") {
+		return nil
+	}
+	return os.WriteFile(s.csvFile, []byte(header+"\n"+rest), 0644)
+}
+
 func (s *Storage) loadSessionsFromCSV() ([]Session, error) {
-	// If file doesn't exist, return empty slice
-	if _, err := os.Stat(s.csvFile); os.IsNotExist(err) {
-		return []Session{}, nil
+	sessions := []Session{}
+	err := s.EachSession(func(session Session) bool {
+		sessions = append(sessions, session)
+		return true
+	})
+	if err != nil {
+		return nil, err
 	}
+	return sessions, nil
+}
 
+// EachSession streams the stored sessions to fn, one CSV row at a time,
+// until fn returns false. Invalid rows are skipped.
+func (s *Storage) EachSession(fn func(session Session) bool) error {
 	file, err := os.Open(s.csvFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer file.Close()
 
 	reader := csv.NewReader(file)
+	// Files written before start/end/project columns existed have only 3 fields
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
 
 	// Read and skip header
 	if _, err := reader.Read(); err != nil {
-		return nil, err
+		if err == io.EOF {
+			return nil
+		}
+		return err
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		session, ok := parseCSVSession(record)
+		if !ok {
+			continue // Skip invalid records
+		}
+		if !fn(session) {
+			return nil
+		}
 	}
+}
 
-	var sessions []Session
-	records, err := reader.ReadAll()
+// parseCSVSession converts a CSV record to a session
+func parseCSVSession(record []string) (Session, bool) {
+	if len(record) < 3 {
+		return Session{}, false
+	}
+
+	duration, err := strconv.ParseInt(record[1], 10, 64)
 	if err != nil {
-		return nil, err
+		return Session{}, false
 	}
 
-	for _, record := range records {
-		if len(record) != 3 {
-			continue // Skip invalid records
+	breakTime, err := strconv.ParseInt(record[2], 10, 64)
+	if err != nil {
+		return Session{}, false
+	}
+
+	session := Session{
+		Date:      record[0],
+		Duration:  duration,
+		BreakTime: breakTime,
+	}
+	if len(record) >= 5 {
+		session.Start = parseCSVTime(record[3])
+		session.End = parseCSVTime(record[4])
+	}
+	if len(record) >= 7 {
+		session.Project = record[5]
+		session.Description = record[6]
+	}
+	if len(record) >= 8 {
+		session.Breaks = parseCSVBreaks(record[7])
+	}
+	// Times are stored in UTC; show them in the recorded zone. Rows without a zone
+	// keep the offset they were written with.
+	if len(record) >= 9 {
+		if loc, ok := loadZone(record[8]); ok {
+			session = session.In(loc)
+			session.Zone = record[8]
 		}
+	}
+	return session, true
+}
 
-		duration, err := strconv.ParseInt(record[1], 10, 64)
-		if err != nil {
+// formatCSVTime formats a timestamp in UTC for the CSV file, leaving zero times empty
+func formatCSVTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// formatCSVBreaks encodes break periods as "start/end" pairs separated by ";"
+func formatCSVBreaks(breaks []Break) string {
+	parts := make([]string, len(breaks))
+	for i, b := range breaks {
+		parts[i] = formatCSVTime(b.Start) + "/" + formatCSVTime(b.End)
+	}
+	return strings.Join(parts, ";")
+}
+
+// parseCSVBreaks decodes break periods written by formatCSVBreaks, skipping invalid entries
+func parseCSVBreaks(value string) []Break {
+	var breaks []Break
+	for _, part := range strings.Split(value, ";") {
+		start, end, ok := strings.Cut(part, "/")
+		if !ok {
 			continue
 		}
-
-		breakTime, err := strconv.ParseInt(record[2], 10, 64)
-		if err != nil {
+		b := Break{Start: parseCSVTime(start), End: parseCSVTime(end)}
+		if b.Start.IsZero() || b.End.IsZero() {
 			continue
 		}
-
-		sessions = append(sessions, Session{
-			Date:      record[0],
-			Duration:  duration,
-			BreakTime: breakTime,
-		})
+		breaks = append(breaks, b)
 	}
+	return breaks
+}
 
-	return sessions, nil
+// parseCSVTime parses a timestamp from the CSV file, returning zero on empty or invalid input
+func parseCSVTime(value string) time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
 }
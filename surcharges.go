@@ -0,0 +1,233 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SurchargeRule selects worked minutes that are paid at a premium. All given conditions
+// must match; a minute can fall into several buckets, e.g. a Sunday night.
+type SurchargeRule struct {
+	Name string `json:"name"`
+	// From and To limit the rule to a time of day ("HH:MM"); 20:00 to 06:00 runs past midnight
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+	// Days limits the rule to weekdays, e.g. ["sun"]
+	Days []string `json:"days,omitempty"`
+	// Holiday limits the rule to public holidays of the configured calendar
+	Holiday bool `json:"holiday,omitempty"`
+	// Multiplier is the pay factor of the selected hours, e.g. 1.25 for a 25% surcharge
+	Multiplier float64 `json:"multiplier"`
+}
+
+// Label describes the rule with its multiplier for reports, e.g. "Night (×1.25)"
+func (r SurchargeRule) Label() string {
+	return fmt.Sprintf("%s (×%g)", r.Name, r.Multiplier)
+}
+
+// surchargeMatcher is a rule with its clock range and weekdays parsed
+type surchargeMatcher struct {
+	from, to time.Duration
+	clock    bool
+	days     map[time.Weekday]bool
+	holiday  bool
+}
+
+func newSurchargeMatcher(rule SurchargeRule) (surchargeMatcher, error) {
+	m := surchargeMatcher{holiday: rule.Holiday}
+	if rule.From != "" || rule.To != "" {
+		from, err := parseClock(rule.From)
+		if err != nil {
+			return m, err
+		}
+		to, err := parseClock(rule.To)
+		if err != nil {
+			return m, err
+		}
+		m.from, m.to, m.clock = from, to, from != to
+	}
+	for _, key := range rule.Days {
+		day, err := parseWeekday(key)
+		if err != nil {
+			return m, err
+		}
+		if m.days == nil {
+			m.days = make(map[time.Weekday]bool)
+		}
+		m.days[day] = true
+	}
+	return m, nil
+}
+
+// matches reports whether a minute at the given clock time and weekday falls under the rule
+func (m surchargeMatcher) matches(clock time.Duration, weekday time.Weekday, holiday bool) bool {
+	if m.holiday && !holiday {
+		return false
+	}
+	if m.days != nil && !m.days[weekday] {
+		return false
+	}
+	if !m.clock {
+		return true
+	}
+	if m.from < m.to {
+		return clock >= m.from && clock < m.to
+	}
+	return clock >= m.from || clock < m.to // Range past midnight
+}
+
+// parseClock parses a time of day ("HH:MM") as time since midnight
+func parseClock(value string) (time.Duration, error) {
+	clock, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, use HH:MM", value)
+	}
+	return time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute, nil
+}
+
+// validateSurcharges checks the "surcharges" setting
+func validateSurcharges(rules []SurchargeRule) error {
+	for i, rule := range rules {
+		if strings.TrimSpace(rule.Name) == "" {
+			return fmt.Errorf("surcharges: rule %d needs a name", i+1)
+		}
+		if rule.Multiplier <= 0 {
+			return fmt.Errorf("surcharges: %s: multiplier must be positive", rule.Name)
+		}
+		if _, err := newSurchargeMatcher(rule); err != nil {
+			return fmt.Errorf("surcharges: %s: %w", rule.Name, err)
+		}
+	}
+	return nil
+}
+
+// SurchargeBucket is the worked time that falls under one surcharge rule
+type SurchargeBucket struct {
+	Rule   SurchargeRule
+	Worked time.Duration
+}
+
+// Weighted returns the worked time multiplied by the rule's pay factor
+func (b SurchargeBucket) Weighted() time.Duration {
+	return time.Duration(float64(b.Worked) * b.Rule.Multiplier).Round(time.Second)
+}
+
+// surchargeClassifier sorts the worked minutes of sessions into the configured buckets
+type surchargeClassifier struct {
+	config   *Config
+	matchers []surchargeMatcher
+	holidays map[string]bool
+}
+
+func newSurchargeClassifier(config *Config) *surchargeClassifier {
+	c := &surchargeClassifier{config: config, holidays: make(map[string]bool)}
+	for _, rule := range config.Surcharges {
+		m, err := newSurchargeMatcher(rule)
+		if err != nil {
+			m = surchargeMatcher{days: map[time.Weekday]bool{}} // Invalid rules match nothing
+		}
+		c.matchers = append(c.matchers, m)
+	}
+	return c
+}
+
+// isHoliday caches the holiday lookup per calendar day
+func (c *surchargeClassifier) isHoliday(t time.Time) bool {
+	date := t.Format("2006-01-02")
+	holiday, ok := c.holidays[date]
+	if !ok {
+		_, holiday = c.config.HolidayOn(t)
+		c.holidays[date] = holiday
+	}
+	return holiday
+}
+
+// Classify returns the worked time of the session per rule, in the order of the rules.
+// Minutes are judged by the clock in the session's recorded zone. Sessions without start
+// and end times can't be classified and give ok false. If only the total break time is
+// known, the buckets are reduced in proportion to it.
+func (c *surchargeClassifier) Classify(session Session) (worked []time.Duration, ok bool) {
+	if session.Start.IsZero() || !session.End.After(session.Start) {
+		return nil, false
+	}
+	worked = make([]time.Duration, len(c.matchers))
+	if len(c.matchers) == 0 {
+		return worked, true
+	}
+	// Reports may show the session in another zone; classify by the recorded one
+	loc := session.Start.Location()
+	if recorded, ok := loadZone(session.Zone); ok {
+		loc = recorded
+	}
+
+	for t := session.Start; t.Before(session.End); {
+		next := t.Truncate(time.Minute).Add(time.Minute)
+		if next.After(session.End) {
+			next = session.End
+		}
+		for _, b := range session.Breaks {
+			// Step to break boundaries, so breaks are cut out exactly
+			for _, edge := range []time.Time{b.Start, b.End} {
+				if edge.After(t) && edge.Before(next) {
+					next = edge
+				}
+			}
+		}
+		if !inBreak(session.Breaks, t) {
+			local := t.In(loc)
+			hour, minute, _ := local.Clock()
+			clock := time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute
+			holiday := c.isHoliday(local)
+			for i, m := range c.matchers {
+				if m.matches(clock, local.Weekday(), holiday) {
+					worked[i] += next.Sub(t)
+				}
+			}
+		}
+		t = next
+	}
+
+	if len(session.Breaks) == 0 && session.BreakTime > 0 {
+		span := session.End.Sub(session.Start)
+		work := time.Duration(session.Duration-session.BreakTime) * time.Second
+		for i := range worked {
+			worked[i] = time.Duration(float64(worked[i]) * float64(work) / float64(span)).Round(time.Second)
+		}
+	}
+	return worked, true
+}
+
+// inBreak reports whether t lies within one of the breaks
+func inBreak(breaks []Break, t time.Time) bool {
+	for _, b := range breaks {
+		if !t.Before(b.Start) && t.Before(b.End) {
+			return true
+		}
+	}
+	return false
+}
+
+// classifySurcharges sums the surcharge buckets of the sessions; the second result is the
+// worked time of sessions without start and end times, which can't be classified
+func classifySurcharges(config *Config, sessions []Session) ([]SurchargeBucket, time.Duration) {
+	classifier := newSurchargeClassifier(config)
+	buckets := make([]SurchargeBucket, len(config.Surcharges))
+	for i, rule := range config.Surcharges {
+		buckets[i].Rule = rule
+	}
+	var unclassified time.Duration
+	for _, session := range sessions {
+		worked, ok := classifier.Classify(session)
+		if !ok {
+			if work := session.Duration - session.BreakTime; work > 0 {
+				unclassified += time.Duration(work) * time.Second
+			}
+			continue
+		}
+		for i := range buckets {
+			buckets[i].Worked += worked[i]
+		}
+	}
+	return buckets, unclassified
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testSurcharges = []SurchargeRule{
+	{Name: "Night", From: "20:00", To: "06:00", Multiplier: 1.25},
+	{Name: "Sunday", Days: []string{"sun"}, Multiplier: 1.5},
+	{Name: "Holiday", Holiday: true, Multiplier: 2},
+}
+
+func TestClassifySurcharges(t *testing.T) {
+	berlin, _ := loadZone("Europe/Berlin")
+	config := &Config{Holidays: "de", Surcharges: testSurcharges}
+	if err := config.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Saturday 2026-10-03 is German Unity Day; the shift runs into Sunday
+	start := time.Date(2026, 10, 3, 18, 0, 0, 0, berlin)
+	shift := Session{
+		Date: "2026-10-03", Duration: 8 * 3600, BreakTime: 1800, Zone: "Europe/Berlin",
+		Start: start, End: start.Add(8 * time.Hour),
+		Breaks: []Break{{Start: start.Add(4 * time.Hour), End: start.Add(4*time.Hour + 30*time.Minute)}},
+	}
+	// Only the total break time is known, so the night hours are reduced in proportion
+	legacyStart := time.Date(2026, 10, 5, 20, 0, 0, 0, berlin)
+	legacy := Session{Date: "2026-10-05", Duration: 2 * 3600, BreakTime: 1800, Start: legacyStart, End: legacyStart.Add(2 * time.Hour)}
+	imported := Session{Date: "2026-10-06", Duration: 3 * 3600}
+
+	buckets, unclassified := classifySurcharges(config, []Session{shift, legacy, imported})
+	expected := []time.Duration{
+		5*time.Hour + 30*time.Minute + 90*time.Minute, // 20:00-02:00 without the break, plus 1:30 on Monday
+		2 * time.Hour,                // Sunday 00:00-02:00
+		5*time.Hour + 30*time.Minute, // Saturday 18:00-24:00 without the break
+	}
+	for i, bucket := range buckets {
+		if bucket.Worked != expected[i] {
+			t.Errorf("%s: expected %v, got %v", bucket.Rule.Name, expected[i], bucket.Worked)
+		}
+	}
+	if unclassified != 3*time.Hour {
+		t.Errorf("Expected 3h without times, got %v", unclassified)
+	}
+	if weighted := buckets[2].Weighted(); weighted != 11*time.Hour {
+		t.Errorf("Expected 11h weighted holiday hours, got %v", weighted)
+	}
+
+	// The recorded zone decides, even if the session is shown in another one
+	tokyo, _ := loadZone("Asia/Tokyo")
+	shown, _ := classifySurcharges(config, []Session{shift.In(tokyo)})
+	if shown[0].Worked != 5*time.Hour+30*time.Minute {
+		t.Errorf("Expected the night hours of the recorded zone, got %v", shown[0].Worked)
+	}
+
+	for _, rule := range []SurchargeRule{
+		{Name: "", Multiplier: 1.5},
+		{Name: "Night", From: "20:00", To: "6", Multiplier: 1.25},
+		{Name: "Sunday", Days: []string{"sunday-ish"}, Multiplier: 1.5},
+		{Name: "Free", Multiplier: 0},
+	} {
+		if err := (&Config{Surcharges: []SurchargeRule{rule}}).Validate(); err == nil {
+			t.Errorf("Expected %+v to fail validation", rule)
+		}
+	}
+}
+
+func TestSurchargesInReports(t *testing.T) {
+	storage := newTestStorage(t)
+	if err := storage.SaveConfig(&Config{WeeklyTargetHours: 40, Holidays: "de", Surcharges: testSurcharges}); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2026, 10, 4, 19, 0, 0, 0, time.Local) // Sunday
+	session := Session{Date: "2026-10-04", Duration: 3 * 3600, Start: start, End: start.Add(3 * time.Hour)}
+	if err := storage.appendSessionsToCSV([]Session{session}); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := storage.PeriodReport(time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local), time.Date(2026, 10, 31, 0, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Surcharges) != 3 || report.Surcharges[0].Worked != 2*time.Hour || report.Surcharges[1].Worked != 3*time.Hour {
+		t.Fatalf("Unexpected surcharges: %+v", report.Surcharges)
+	}
+	var buf bytes.Buffer
+	if err := WriteHTMLReport(&buf, report); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "<tr><td>Sunday</td><td>3:00:00</td><td>×1.5</td><td>4:30:00</td></tr>") {
+		t.Errorf("Expected the Sunday bucket in the HTML report")
+	}
+
+	sheet, err := storage.Timesheet(2026, time.October)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	if err := WriteTimesheetPDF(&buf, sheet); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "(Sunday \\(\\327") {
+		t.Errorf("Expected the Sunday bucket in the PDF timesheet")
+	}
+
+	summaries, err := storage.MonthlySummaries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(summaries) != 1 || summaries[0].Surcharges[0] != 2*time.Hour {
+		t.Errorf("Expected 2h night work in the monthly summary, got %+v", summaries)
+	}
+}
@@ -6,9 +6,22 @@ import (
 )
 
 type Session struct {
-	Date      string `json:"date"`
-	Duration  int64  `json:"duration_s"`
-	BreakTime int64  `json:"break_time_s"`
+	Date        string    `json:"date"`
+	Duration    int64     `json:"duration_s"`
+	BreakTime   int64     `json:"break_time_s"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	Project     string    `json:"project,omitempty"`
+	Description string    `json:"description,omitempty"`
+	Breaks      []Break   `json:"breaks,omitempty"`
+	// Zone is the time zone the session was recorded in, an IANA name or a UTC offset
+	Zone string `json:"zone,omitempty"`
+}
+
+// Break is a single pause within a session
+type Break struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
 }
 
 type Timer struct {
@@ -22,8 +35,16 @@ type Timer struct {
 	DailyTotal          time.Duration `json:"daily_total"`
 	YesterdayTotal      time.Duration `json:"yesterday_total"`
 	YesterdayFirstStart time.Time     `json:"yesterday_first_start"`
+	DayLastEnd          time.Time     `json:"day_last_end"`
+	YesterdayLastEnd    time.Time     `json:"yesterday_last_end"`
 	storage             *Storage
 	weeklyTotal         time.Duration
+	monthlyTotal        time.Duration
+	flextimeBalance     time.Duration // up to yesterday, plus today's bookings
+	todayRemaining      time.Duration // today's target minus the completed sessions of today
+	config              *Config
+	absences            Absences
+	recentSessions      []Session // completed sessions of yesterday and today
 }
 
 func NewTimer() *Timer {
@@ -32,16 +53,16 @@ func NewTimer() *Timer {
 	}
 }
 
-// SetStorage sets the storage instance for the timer and updates weekly total
+// SetStorage sets the storage instance for the timer and updates the cached totals
 func (t *Timer) SetStorage(s *Storage) {
 	t.storage = s
-	t.updateWeeklyTotal() // Initialize weekly total on storage set
+	t.updateTotals() // Initialize weekly and monthly totals on storage set
 }
 
 func (t *Timer) Start() {
 	if !t.IsRunning {
 		now := time.Now()
-		currentDate := now.Format("2006-01-02")
+		currentDate := t.logicalDate(now)
 
 		// Check for day transition
 		t.checkAndHandleDayTransition()
@@ -53,7 +74,9 @@ func (t *Timer) Start() {
 		}
 
 		t.TodaySession = &Session{
-			Date: currentDate,
+			Date:  currentDate,
+			Start: now,
+			Zone:  recordedZone(now),
 		}
 		t.SessionStart = now
 		t.IsRunning = true
@@ -66,15 +89,18 @@ func (t *Timer) Stop() {
 			t.StopBreak()
 		}
 
-		sessionDuration := time.Since(t.SessionStart)
+		now := time.Now()
+		sessionDuration := now.Sub(t.SessionStart)
 		breakDuration := time.Duration(t.TodaySession.BreakTime) * time.Second
 		workDuration := sessionDuration - breakDuration
 
 		t.TodaySession.Duration = int64(sessionDuration.Seconds())
+		t.TodaySession.End = now
 		// Only store sessions longer than 1 second
 		if t.TodaySession.Duration > 1 {
 			t.Sessions = append(t.Sessions, *t.TodaySession)
-			t.updateWeeklyTotal() // Update weekly total when adding new session
+			t.updateTotals() // Update weekly and monthly totals when adding new session
+			t.DayLastEnd = now
 
 			// Update daily total
 			if workDuration > 0 {
@@ -95,12 +121,32 @@ func (t *Timer) StartBreak() {
 
 func (t *Timer) StopBreak() {
 	if t.IsOnBreak {
-		breakDuration := time.Since(t.BreakStart).Seconds()
+		now := time.Now()
+		breakDuration := now.Sub(t.BreakStart).Seconds()
 		t.TodaySession.BreakTime += int64(breakDuration)
+		t.TodaySession.Breaks = append(t.TodaySession.Breaks, Break{Start: t.BreakStart, End: now})
 		t.IsOnBreak = false
 	}
 }
 
+// ToggleRunning stops a running session or starts a new one
+func (t *Timer) ToggleRunning() {
+	if t.IsRunning {
+		t.Stop()
+	} else {
+		t.Start()
+	}
+}
+
+// ToggleBreak ends the current break or starts one if a session is running
+func (t *Timer) ToggleBreak() {
+	if t.IsOnBreak || !t.IsRunning {
+		t.StopBreak()
+	} else {
+		t.StartBreak()
+	}
+}
+
 func (t *Timer) Reset() {
 	// Don't save the current session when resetting
 	t.TodaySession = nil
@@ -129,52 +175,83 @@ func (t *Timer) GetCurrentBreakTime() time.Duration {
 	return time.Duration(t.TodaySession.BreakTime) * time.Second
 }
 
-// updateWeeklyTotal recalculates and caches the weekly total
-func (t *Timer) updateWeeklyTotal() {
-	var total time.Duration
-	currentWeek := t.getWeekNumber(time.Now())
-	currentYear := time.Now().Year()
+// updateTotals recalculates and caches the weekly and monthly totals and the flextime balance
+func (t *Timer) updateTotals() {
+	var weekly, monthly time.Duration
+	now := time.Now()
 
 	// Load historical sessions from CSV
+	var sessions []Session
+	var absences Absences
+	var bookings []Booking
+	config := DefaultConfig()
 	if t.storage != nil {
-		if sessions, err := t.storage.loadSessionsFromCSV(); err == nil {
-			// Sum up completed sessions from this week
-			for _, session := range sessions {
-				sessionTime, err := time.Parse("2006-01-02", session.Date)
-				if err != nil {
-					continue // Skip invalid dates
-				}
-				sessionWeek := t.getWeekNumber(sessionTime)
-				sessionYear := sessionTime.Year()
-
-				if sessionWeek == currentWeek && sessionYear == currentYear {
-					durationDiff := session.Duration - session.BreakTime
-					if durationDiff > 0 { // Only count positive durations
-						total += time.Duration(durationDiff) * time.Second
-					}
-				}
-			}
+		if stored, err := t.storage.loadSessionsFromCSV(); err == nil {
+			sessions = stored
+		}
+		if loaded, err := t.storage.LoadConfig(); err == nil {
+			config = loaded
+		}
+		if loaded, err := t.storage.LoadAbsences(); err == nil {
+			absences = loaded
+		}
+		if loaded, err := t.storage.allBookings(config, absences); err == nil {
+			bookings = loaded
 		}
 	}
 
 	// Add completed sessions from memory that haven't been saved yet
-	for _, session := range t.Sessions {
-		sessionTime, err := time.Parse("2006-01-02", session.Date)
+	sessions = append(sessions, t.Sessions...)
+
+	// Sessions are stored under their working day, so compare against today's working day
+	today := config.LogicalDay(now)
+	currentYear, currentWeek := config.WeekOf(today)
+	for _, session := range sessions {
+		sessionTime, err := time.ParseInLocation("2006-01-02", session.Date, time.Local)
 		if err != nil {
 			continue // Skip invalid dates
 		}
-		sessionWeek := t.getWeekNumber(sessionTime)
-		sessionYear := sessionTime.Year()
+		durationDiff := session.Duration - session.BreakTime
+		if durationDiff <= 0 { // Only count positive durations
+			continue
+		}
+		work := time.Duration(durationDiff) * time.Second
 
-		if sessionWeek == currentWeek && sessionYear == currentYear {
-			durationDiff := session.Duration - session.BreakTime
-			if durationDiff > 0 { // Only count positive durations
-				total += time.Duration(durationDiff) * time.Second
-			}
+		// Compare the week's own year, which differs from the calendar year around New Year
+		if year, week := config.WeekOf(sessionTime); year == currentYear && week == currentWeek {
+			weekly += work
+		}
+		if sessionTime.Month() == today.Month() && sessionTime.Year() == today.Year() {
+			monthly += work
+		}
+	}
+
+	t.weeklyTotal = weekly
+	t.monthlyTotal = monthly
+	t.config = config
+	t.absences = absences
+
+	yesterday := today.AddDate(0, 0, -1).Format("2006-01-02")
+	t.recentSessions = nil
+	for _, session := range sessions {
+		if session.Date >= yesterday {
+			t.recentSessions = append(t.recentSessions, session)
 		}
 	}
 
-	t.weeklyTotal = total
+	// Today only adds overtime (see computeFlextime); the overtime of today's completed
+	// sessions is taken out here and added with the running session in GetFlextimeBalance
+	worked := dailyWork(sessions)
+	start := flextimeStart(config, worked)
+	if start.IsZero() {
+		start = today
+	}
+	t.flextimeBalance = computeFlextime(config, absences, bookings, worked, start, today, today).Balance
+	t.todayRemaining = 0
+	if !today.Before(start) {
+		t.todayRemaining = absences.Target(config, today) - worked[today.Format("2006-01-02")]
+		t.flextimeBalance += min(t.todayRemaining, 0)
+	}
 }
 
 func (t *Timer) GetWeeklyTime() time.Duration {
@@ -183,12 +260,10 @@ func (t *Timer) GetWeeklyTime() time.Duration {
 
 	// Add current session if running
 	if t.IsRunning && t.TodaySession != nil {
-		sessionTime, err := time.Parse("2006-01-02", t.TodaySession.Date)
+		sessionTime, err := time.ParseInLocation("2006-01-02", t.TodaySession.Date, time.Local)
 		if err == nil { // Only add if date is valid
-			sessionWeek := t.getWeekNumber(sessionTime)
-			sessionYear := sessionTime.Year()
-			currentWeek := t.getWeekNumber(time.Now())
-			currentYear := time.Now().Year()
+			sessionYear, sessionWeek := t.getWeekNumber(sessionTime)
+			currentYear, currentWeek := t.getWeekNumber(t.logicalDay(time.Now()))
 
 			if sessionWeek == currentWeek && sessionYear == currentYear {
 				// Calculate current session duration excluding breaks
@@ -208,9 +283,73 @@ func (t *Timer) GetWeeklyTime() time.Duration {
 	return total
 }
 
-func (t *Timer) getWeekNumber(date time.Time) int {
-	_, week := date.ISOWeek()
-	return week
+// GetMonthlyTime returns the month-to-date total including the running session
+func (t *Timer) GetMonthlyTime() time.Duration {
+	total := t.monthlyTotal
+
+	if t.IsRunning && t.TodaySession != nil {
+		sessionTime, err := time.ParseInLocation("2006-01-02", t.TodaySession.Date, time.Local)
+		today := t.logicalDay(time.Now())
+		if err == nil && sessionTime.Month() == today.Month() && sessionTime.Year() == today.Year() {
+			// Calculate current session duration excluding breaks
+			currentDuration := time.Since(t.SessionStart)
+			totalBreakTime := time.Duration(t.TodaySession.BreakTime) * time.Second
+			if t.IsOnBreak {
+				totalBreakTime += time.Since(t.BreakStart)
+			}
+			workDuration := currentDuration - totalBreakTime
+			if workDuration > 0 {
+				total += workDuration
+			}
+		}
+	}
+
+	return total
+}
+
+// GetFlextimeBalance returns the overtime balance up to yesterday plus today's overtime,
+// i.e. the time worked today (including the running session) beyond today's target.
+// Today's undertime only counts once the day is over.
+func (t *Timer) GetFlextimeBalance() time.Duration {
+	balance := t.flextimeBalance
+	var workDuration time.Duration
+
+	if t.IsRunning && t.TodaySession != nil {
+		currentDuration := time.Since(t.SessionStart)
+		totalBreakTime := time.Duration(t.TodaySession.BreakTime) * time.Second
+		if t.IsOnBreak {
+			totalBreakTime += time.Since(t.BreakStart)
+		}
+		workDuration = currentDuration - totalBreakTime
+	}
+	if overtime := workDuration - t.todayRemaining; overtime > 0 {
+		balance += overtime
+	}
+
+	return balance
+}
+
+// getWeekNumber returns the year and number of the week containing date, using the configured week start
+func (t *Timer) getWeekNumber(date time.Time) (year, week int) {
+	config := t.config
+	if config == nil {
+		config = DefaultConfig()
+	}
+	return config.WeekOf(date)
+}
+
+// logicalDay returns midnight of the working day containing date, using the configured day start
+func (t *Timer) logicalDay(date time.Time) time.Time {
+	config := t.config
+	if config == nil {
+		config = DefaultConfig()
+	}
+	return config.LogicalDay(date)
+}
+
+// logicalDate returns the working day containing date as YYYY-MM-DD
+func (t *Timer) logicalDate(date time.Time) string {
+	return t.logicalDay(date).Format("2006-01-02")
 }
 
 func (t *Timer) MarshalJSON() ([]byte, error) {
@@ -272,13 +411,15 @@ func (t *Timer) GetDayFirstStartTime() string {
 // Add method to handle day transition
 func (t *Timer) checkAndHandleDayTransition() {
 	now := time.Now()
-	if !t.DayFirstStart.IsZero() && t.DayFirstStart.Format("2006-01-02") != now.Format("2006-01-02") {
+	if !t.DayFirstStart.IsZero() && t.logicalDate(t.DayFirstStart) != t.logicalDate(now) {
 		// Store yesterday's data before resetting
 		t.YesterdayTotal = t.DailyTotal
 		t.YesterdayFirstStart = t.DayFirstStart
+		t.YesterdayLastEnd = t.DayLastEnd
 
 		// Reset today's tracking
 		t.DayFirstStart = time.Time{}
+		t.DayLastEnd = time.Time{}
 		t.DailyTotal = 0
 	}
 }
@@ -290,3 +431,147 @@ func (t *Timer) GetYesterdayFirstStartTime() string {
 	}
 	return t.YesterdayFirstStart.Format("15:04:05")
 }
+
+// GetLeaveAt projects the clock time at which today's target will be reached,
+// including the part of the required break that has not been taken yet.
+// If no session is running, the projection assumes work is resumed now.
+// reached is true once the target has been worked.
+func (t *Timer) GetLeaveAt(now time.Time) (leaveAt time.Time, reached bool) {
+	config := t.config
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	target := t.absences.Target(config, config.LogicalDay(now))
+	worked := t.GetDailyTime()
+	firstStart := t.DayFirstStart
+	if !t.IsRunning && config.LogicalDate(firstStart) != config.LogicalDate(now) {
+		// Nothing worked yet today; the daily total still belongs to an earlier day
+		worked, firstStart = 0, time.Time{}
+	}
+	if worked >= target {
+		return now, true
+	}
+
+	// Everything since the first start that wasn't work counts as break,
+	// including the gaps between sessions and a break in progress
+	var breakTaken time.Duration
+	if !firstStart.IsZero() {
+		breakTaken = now.Sub(firstStart) - worked
+	}
+
+	breakDue := config.RequiredBreak(target) - breakTaken
+	if breakDue < 0 {
+		breakDue = 0
+	}
+	return now.Add(target - worked + breakDue), false
+}
+
+// GetLeaveAtTime returns the projected time at which today's target is reached, formatted for display
+func (t *Timer) GetLeaveAtTime() string {
+	now := time.Now()
+	config := t.config
+	if config == nil {
+		config = DefaultConfig()
+	}
+	if t.absences.Target(config, config.LogicalDay(now)) == 0 {
+		return "No target today"
+	}
+	leaveAt, reached := t.GetLeaveAt(now)
+	if reached {
+		return "Target reached"
+	}
+	return leaveAt.Format("15:04")
+}
+
+// currentSession returns a snapshot of the running session as if it were stopped now
+func (t *Timer) currentSession(now time.Time) (Session, bool) {
+	if !t.IsRunning || t.TodaySession == nil {
+		return Session{}, false
+	}
+	session := *t.TodaySession
+	session.Duration = int64(now.Sub(t.SessionStart).Seconds())
+	session.End = now
+	if t.IsOnBreak {
+		session.BreakTime += int64(now.Sub(t.BreakStart).Seconds())
+	}
+	return session, true
+}
+
+// GetComplianceWarnings checks today's sessions, including the running one, against the configured rules
+func (t *Timer) GetComplianceWarnings(now time.Time) []Violation {
+	config := t.config
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	sessions := append([]Session{}, t.recentSessions...)
+	if session, ok := t.currentSession(now); ok {
+		sessions = append(sessions, session)
+	}
+
+	var warnings []Violation
+	today := config.LogicalDate(now)
+	for _, violation := range CheckCompliance(config.ComplianceRules(), buildWorkDays(sessions)) {
+		if violation.Date == today {
+			warnings = append(warnings, violation)
+		}
+	}
+	return warnings
+}
+
+// Add method to get yesterday's last end time
+func (t *Timer) GetYesterdayLastEndTime() string {
+	if t.YesterdayLastEnd.IsZero() {
+		return "No data"
+	}
+	return t.YesterdayLastEnd.Format("15:04:05")
+}
+
+// GetRestSinceLastShift returns the rest between the end of the previous working day and
+// today's first start, or the rest so far if work hasn't started today. ok is false if unknown.
+func (t *Timer) GetRestSinceLastShift(now time.Time) (rest time.Duration, ok bool) {
+	today := t.logicalDate(now)
+	if !t.DayFirstStart.IsZero() && t.logicalDate(t.DayFirstStart) == today {
+		if t.YesterdayLastEnd.IsZero() {
+			return 0, false
+		}
+		return t.DayFirstStart.Sub(t.YesterdayLastEnd), true
+	}
+
+	// Not started today; without a day transition yet the last shift is still in DayLastEnd
+	lastEnd := t.DayLastEnd
+	if lastEnd.IsZero() {
+		lastEnd = t.YesterdayLastEnd
+	}
+	if lastEnd.IsZero() || t.IsRunning {
+		return 0, false
+	}
+	return now.Sub(lastEnd), true
+}
+
+// GetRestTime returns the rest since the last shift formatted for display
+func (t *Timer) GetRestTime() string {
+	rest, ok := t.GetRestSinceLastShift(time.Now())
+	if !ok {
+		return "No data"
+	}
+	return formatDuration(rest)
+}
+
+// CheckRestBeforeStart reports whether starting now would cut the configured minimum rest short
+func (t *Timer) CheckRestBeforeStart(now time.Time) (rest, minimum time.Duration, short bool) {
+	config := t.config
+	if config == nil {
+		config = DefaultConfig()
+	}
+	minimum = config.MinRest()
+	if minimum == 0 || t.IsRunning {
+		return 0, minimum, false
+	}
+	if !t.DayFirstStart.IsZero() && config.LogicalDate(t.DayFirstStart) == config.LogicalDate(now) {
+		return 0, minimum, false // Already worked today, the rest was checked at the first start
+	}
+	rest, ok := t.GetRestSinceLastShift(now)
+	return rest, minimum, ok && rest < minimum
+}
@@ -173,3 +173,79 @@ func TestWeeklyTotalCache(t *testing.T) {
 		t.Errorf("Weekly total should be at least 6 seconds, got %v", total1)
 	}
 }
+
+func TestTimerRecordsBreaks(t *testing.T) {
+	timer := NewTimer()
+	timer.Start()
+	timer.StartBreak()
+	timer.StopBreak()
+	timer.StartBreak()
+	timer.StopBreak()
+
+	if len(timer.TodaySession.Breaks) != 2 {
+		t.Fatalf("Expected 2 recorded breaks, got %d", len(timer.TodaySession.Breaks))
+	}
+	if timer.TodaySession.Start.IsZero() {
+		t.Error("Session start should be recorded")
+	}
+}
+
+func TestTimerMonthlyTotal(t *testing.T) {
+	storage := newTestStorage(t)
+	now := time.Now()
+	lastMonth := now.AddDate(0, 0, -now.Day()) // last day of the previous month
+	sessions := []Session{
+		{Date: now.Format("2006-01-02"), Duration: 3600, BreakTime: 600},
+		{Date: lastMonth.Format("2006-01-02"), Duration: 7200},
+	}
+	if err := storage.appendSessionsToCSV(sessions); err != nil {
+		t.Fatal(err)
+	}
+
+	timer := NewTimer()
+	timer.SetStorage(storage)
+	if total := timer.GetMonthlyTime(); total != 50*time.Minute {
+		t.Errorf("Monthly total should only include this month's work, got %v", total)
+	}
+}
+
+func TestLeaveAtProjection(t *testing.T) {
+	everyDay := map[string]float64{"mon": 8, "tue": 8, "wed": 8, "thu": 8, "fri": 8, "sat": 8, "sun": 8}
+	timer := NewTimer()
+	timer.config = &Config{WeekdayTargetHours: everyDay, RequiredBreaks: defaultRequiredBreaks}
+
+	now := time.Now()
+	timer.IsRunning = true
+	timer.SessionStart = now.Add(-2 * time.Hour)
+	timer.DayFirstStart = timer.SessionStart
+	timer.TodaySession = &Session{Date: now.Format("2006-01-02"), Start: timer.SessionStart}
+
+	assertLeaveAt := func(want time.Time) {
+		t.Helper()
+		got, reached := timer.GetLeaveAt(time.Now())
+		if reached {
+			t.Fatal("Target should not be reached yet")
+		}
+		if diff := got.Sub(want); diff < -2*time.Second || diff > 2*time.Second {
+			t.Errorf("Expected leave at %v, got %v", want.Format("15:04:05"), got.Format("15:04:05"))
+		}
+	}
+
+	// 6h of work and the full 30 minute break are still ahead
+	assertLeaveAt(now.Add(6*time.Hour + 30*time.Minute))
+
+	// A running 20 minute break leaves 10 minutes of required break
+	timer.SessionStart = now.Add(-2*time.Hour - 20*time.Minute)
+	timer.DayFirstStart = timer.SessionStart
+	timer.IsOnBreak = true
+	timer.BreakStart = now.Add(-20 * time.Minute)
+	assertLeaveAt(now.Add(6*time.Hour + 10*time.Minute))
+
+	// Once the target is worked it is reached
+	timer.IsOnBreak = false
+	timer.SessionStart = now.Add(-9 * time.Hour)
+	timer.DayFirstStart = timer.SessionStart
+	if _, reached := timer.GetLeaveAt(time.Now()); !reached {
+		t.Error("Target should be reached after 9 hours")
+	}
+}
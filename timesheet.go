@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	timesheetMargin     = 56.0
+	timesheetRowHeight  = 15.0
+	timesheetFontSize   = 9.0
+	timesheetTitleSize  = 16.0
+	timesheetSignatureW = 200.0
+	timesheetNoteX      = 75.0
+)
+
+// timesheetColumns are the table headers with the right edge of each column;
+// the date column is left-aligned at the margin
+var timesheetColumns = []struct {
+	title string
+	right float64
+}{
+	{"Date", 0},
+	{"Start", 230},
+	{"End", 290},
+	{"Breaks", 360},
+	{"Net hours", 440},
+	{"Target", 510},
+}
+
+// Timesheet is the data of one monthly timesheet
+type Timesheet struct {
+	Year   int
+	Month  time.Month
+	Report *PeriodReport
+	// Balance is the flextime balance at the end of the month (or today for the current month)
+	Balance time.Duration
+}
+
+// Timesheet collects the days and balance of the given month
+func (s *Storage) Timesheet(year int, month time.Month) (*Timesheet, error) {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.Local)
+	last := first.AddDate(0, 1, -1)
+	report, err := s.PeriodReport(first, last)
+	if err != nil {
+		return nil, err
+	}
+
+	balanceEnd := last
+	today, err := s.Today()
+	if err != nil {
+		return nil, err
+	}
+	if today.Before(last) {
+		balanceEnd = today
+	}
+	flextime, err := s.Flextime(balanceEnd)
+	if err != nil {
+		return nil, err
+	}
+	return &Timesheet{Year: year, Month: month, Report: report, Balance: flextime.Balance}, nil
+}
+
+// WriteTimesheetPDF writes a one-page A4 timesheet with signature lines;
+// the output depends only on the timesheet, so it is reproducible
+func WriteTimesheetPDF(w io.Writer, sheet *Timesheet) error {
+	var doc pdfDocument
+	doc.AddPage()
+	left, right := timesheetMargin, pdfPageWidth-timesheetMargin
+
+	y := timesheetMargin + timesheetTitleSize
+	doc.Text(left, y, timesheetTitleSize, true, fmt.Sprintf("Timesheet %s %d", sheet.Month, sheet.Year))
+	y += 2 * timesheetRowHeight
+
+	for _, column := range timesheetColumns {
+		if column.right == 0 {
+			doc.Text(left, y, timesheetFontSize, true, column.title)
+		} else {
+			doc.TextRight(left+column.right, y, timesheetFontSize, true, column.title)
+		}
+	}
+	doc.Line(left, y+4, right, y+4, 0.8)
+	y += timesheetRowHeight
+
+	report := sheet.Report
+	for _, day := range report.Days {
+		values := []string{day.Date.Format("Mon 02.01.2006"), "", "", "", "", formatDuration(day.Target)}
+		if len(day.Sessions) > 0 {
+			values[1] = formatClock(day.FirstStart())
+			values[2] = formatClock(day.LastEnd())
+			values[3] = formatDuration(day.Break)
+			values[4] = formatDuration(day.Worked)
+		}
+		if note := day.Note(); note != "" {
+			doc.Text(left+timesheetNoteX, y, timesheetFontSize-1, false, note)
+		}
+		for i, column := range timesheetColumns {
+			if column.right == 0 {
+				doc.Text(left, y, timesheetFontSize, false, values[i])
+			} else {
+				doc.TextRight(left+column.right, y, timesheetFontSize, false, values[i])
+			}
+		}
+		y += timesheetRowHeight
+	}
+	doc.Line(left, y-timesheetRowHeight+4, right, y-timesheetRowHeight+4, 0.8)
+
+	// Totals, right-aligned below the net hours column
+	y += timesheetRowHeight / 2
+	totals := [][2]string{
+		{"Breaks", formatDuration(report.Break)},
+		{"Net hours", formatDuration(report.Worked)},
+		{"Target", formatDuration(report.Target)},
+		{"Overtime this month", formatSignedDuration(report.Delta())},
+		{"Overtime balance", formatSignedDuration(sheet.Balance)},
+	}
+	for _, total := range totals {
+		doc.Text(left, y, timesheetFontSize, true, total[0])
+		doc.TextRight(left+timesheetColumns[4].right, y, timesheetFontSize, false, total[1])
+		y += timesheetRowHeight
+	}
+
+	// Surcharge buckets with the worked and the weighted hours. The sheet stays on one page,
+	// so rows that would reach the signature lines are left out with a note.
+	signatureY := pdfPageHeight - timesheetMargin - timesheetRowHeight
+	lastRowY := signatureY - 1.5*timesheetRowHeight
+	if len(report.Surcharges) > 0 {
+		y += timesheetRowHeight / 2
+		doc.Text(left, y, timesheetFontSize, true, "Surcharges")
+		doc.TextRight(left+timesheetColumns[4].right, y, timesheetFontSize, true, "Hours")
+		doc.TextRight(left+timesheetColumns[5].right, y, timesheetFontSize, true, "Weighted")
+		y += timesheetRowHeight
+		for i, bucket := range report.Surcharges {
+			// Keep room for the note unless this is the last row
+			if remaining := len(report.Surcharges) - i; y > lastRowY || (remaining > 1 && y+timesheetRowHeight > lastRowY) {
+				doc.Text(left, y, timesheetFontSize, false, fmt.Sprintf("%d more surcharge buckets are in the HTML report", remaining))
+				break
+			}
+			doc.Text(left, y, timesheetFontSize, false, bucket.Rule.Label())
+			doc.TextRight(left+timesheetColumns[4].right, y, timesheetFontSize, false, formatDuration(bucket.Worked))
+			doc.TextRight(left+timesheetColumns[5].right, y, timesheetFontSize, false, formatDuration(bucket.Weighted()))
+			y += timesheetRowHeight
+		}
+	}
+
+	// Signature lines at the bottom of the page
+	y = signatureY
+	for i, label := range []string{"Date, signature employee", "Date, signature supervisor"} {
+		x := left + float64(i)*(right-left-timesheetSignatureW)
+		doc.Line(x, y, x+timesheetSignatureW, y, 0.5)
+		doc.Text(x, y+timesheetRowHeight-3, timesheetFontSize-1, false, label)
+	}
+
+	_, err := doc.WriteTo(w)
+	return err
+}
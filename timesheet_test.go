@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimesheetPDFReproducible(t *testing.T) {
+	start := time.Date(2025, 3, 10, 8, 0, 0, 0, time.Local)
+	report := &PeriodReport{
+		From: time.Date(2025, 3, 1, 0, 0, 0, 0, time.Local),
+		To:   time.Date(2025, 3, 31, 0, 0, 0, 0, time.Local),
+		Days: []DaySummary{{
+			Date:     start,
+			Worked:   8 * time.Hour,
+			Break:    45 * time.Minute,
+			Target:   8 * time.Hour,
+			Sessions: []Session{{Date: "2025-03-10", Start: start, End: start.Add(8*time.Hour + 45*time.Minute)}},
+		}},
+		Worked: 8 * time.Hour,
+		Break:  45 * time.Minute,
+		Target: 168 * time.Hour,
+	}
+	sheet := &Timesheet{Year: 2025, Month: time.March, Report: report, Balance: 90 * time.Minute}
+
+	var first, second bytes.Buffer
+	if err := WriteTimesheetPDF(&first, sheet); err != nil {
+		t.Fatalf("WriteTimesheetPDF failed: %v", err)
+	}
+	if err := WriteTimesheetPDF(&second, sheet); err != nil {
+		t.Fatalf("WriteTimesheetPDF failed: %v", err)
+	}
+	if !bytes.Equal(first.Bytes(), second.Bytes()) {
+		t.Error("The same timesheet should give identical bytes")
+	}
+
+	pdf := first.String()
+	if !strings.HasPrefix(pdf, "%PDF-1.4\n") || !strings.HasSuffix(pdf, "%%EOF\n") {
+		t.Error("Missing PDF header or trailer")
+	}
+	for _, want := range []string{"(Timesheet March 2025)", "(Mon 10.03.2025)", "(08:00)", "(16:45)", "(0:45:00)", "(+1:30:00)", "(-160:00:00)", "signature supervisor"} {
+		if !strings.Contains(pdf, want) {
+			t.Errorf("PDF should contain %s", want)
+		}
+	}
+
+	// Every cross-reference entry must point at its object
+	xref := strings.Index(pdf, "\nxref\n") + 1
+	if !strings.HasSuffix(pdf, fmt.Sprintf("startxref\n%d\n%%%%EOF\n", xref)) {
+		t.Error("startxref does not point at the xref table")
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllStringSubmatch(pdf[xref:], -1)
+	if len(entries) == 0 {
+		t.Fatal("No xref entries found")
+	}
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(entry[1])
+		if !strings.HasPrefix(pdf[offset:], fmt.Sprintf("%d 0 obj\n", i+1)) {
+			t.Errorf("xref entry %d points at %q", i+1, pdf[offset:offset+10])
+		}
+	}
+}
+
+func TestPDFEscape(t *testing.T) {
+	if got := pdfEscape(`a(b)\ Müller €`); got != `a\(b\)\\ M\374ller ?` {
+		t.Errorf("Unexpected escaping: %s", got)
+	}
+}
+
+func TestTimesheetManySurcharges(t *testing.T) {
+	first := time.Date(2025, 3, 1, 0, 0, 0, 0, time.Local)
+	report := &PeriodReport{From: first, To: first.AddDate(0, 1, -1)}
+	for day := first; day.Month() == time.March; day = day.AddDate(0, 0, 1) {
+		report.Days = append(report.Days, DaySummary{Date: day})
+	}
+	for i := 1; i <= 12; i++ {
+		report.Surcharges = append(report.Surcharges, SurchargeBucket{Rule: SurchargeRule{Name: fmt.Sprintf("Rule %d", i), Multiplier: 1.5}, Worked: time.Hour})
+	}
+
+	var buf bytes.Buffer
+	if err := WriteTimesheetPDF(&buf, &Timesheet{Year: 2025, Month: time.March, Report: report}); err != nil {
+		t.Fatal(err)
+	}
+	pdf := buf.String()
+	if !strings.Contains(pdf, "(Rule 1 \\(\\3271.5\\))") || strings.Contains(pdf, "(Rule 12 ") {
+		t.Error("Expected the first surcharge rows and not the last one")
+	}
+	if !strings.Contains(pdf, "more surcharge buckets are in the HTML report") {
+		t.Error("Expected a note about the rows left out")
+	}
+
+	// Nothing but the signature labels may be drawn at or below the signature lines
+	signatureLine := timesheetMargin + timesheetRowHeight // PDF y grows upwards
+	for _, match := range regexp.MustCompile(`(\S+) Td \((.*?)\) Tj ET`).FindAllStringSubmatch(pdf, -1) {
+		y, err := strconv.ParseFloat(match[1], 64)
+		if err != nil || strings.Contains(match[2], "signature") {
+			continue
+		}
+		if y < signatureLine+timesheetRowHeight {
+			t.Errorf("%q is drawn over the signature lines at y %g", match[2], y)
+		}
+	}
+}
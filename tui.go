@@ -0,0 +1,209 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+const (
+	tuiRefreshInterval = 250 * time.Millisecond
+	tuiMinColumnWidth  = 30
+
+	// ANSI escape sequences
+	ansiAltScreen    = "\x1b[?1049h"
+	ansiMainScreen   = "\x1b[?1049l"
+	ansiHideCursor   = "\x1b[?25l"
+	ansiShowCursor   = "\x1b[?25h"
+	ansiClearAndHome = "\x1b[H\x1b[2J"
+	ansiBold         = "\x1b[1m"
+	ansiReset        = "\x1b[0m"
+
+	// Keyboard shortcuts
+	tuiKeyStartStop = 's'
+	tuiKeyBreak     = 'b'
+	tuiKeyCancel    = 'c'
+	tuiKeyQuit      = 'q'
+	keyCtrlC        = 3
+	keyEscape       = 0x1b
+
+	// Status and help text
+	tuiTextRunning    = "Working"
+	tuiTextOnBreak    = "On break"
+	tuiTextNotRunning = "Not running"
+	tuiTextHelp       = "[s] Start/Stop  [b] Break  [c] Cancel  [q] Quit"
+)
+
+// TUI shows the main window panels in a terminal for SSH and headless machines
+type TUI struct {
+	timer   *Timer
+	storage *Storage
+	in      *os.File
+	out     io.Writer
+	// notice is shown above the help line until the next key press
+	notice string
+	// confirmShortRest is set while a start after a short rest waits for confirmation
+	confirmShortRest bool
+}
+
+func NewTUI(timer *Timer, storage *Storage) *TUI {
+	return &TUI{
+		timer:   timer,
+		storage: storage,
+		in:      os.Stdin,
+		out:     os.Stdout,
+	}
+}
+
+// Run shows the terminal UI until the user quits
+func (tui *TUI) Run() error {
+	fd := int(tui.in.Fd())
+	if !term.IsTerminal(fd) {
+		return fmt.Errorf("standard input is not a terminal")
+	}
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return err
+	}
+	defer term.Restore(fd, state)
+
+	fmt.Fprint(tui.out, ansiAltScreen+ansiHideCursor)
+	defer fmt.Fprint(tui.out, ansiShowCursor+ansiMainScreen)
+
+	keys := make(chan byte)
+	go tui.readKeys(keys)
+
+	ticker := time.NewTicker(tuiRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		width, _, err := term.GetSize(fd)
+		if err != nil {
+			width = 2 * tuiMinColumnWidth
+		}
+		fmt.Fprint(tui.out, ansiClearAndHome+strings.ReplaceAll(tui.render(width), "\n", "\r\n"))
+
+		select {
+		case key, ok := <-keys:
+			if !ok || !tui.handleKey(key) {
+				return tui.storage.SaveTimer(tui.timer)
+			}
+		case <-ticker.C:
+		}
+	}
+}
+
+// readKeys sends the key presses read from the terminal. Escape sequences of arrow and
+// function keys are dropped, as their last byte would match a shortcut (ESC [ C is right).
+func (tui *TUI) readKeys(keys chan<- byte) {
+	defer close(keys)
+	buf := make([]byte, 1)
+	escape := 0 // 1 after ESC, 2 within an ESC [ or ESC O sequence
+	for {
+		if _, err := tui.in.Read(buf); err != nil {
+			return
+		}
+		key := buf[0]
+		switch {
+		case key == keyEscape:
+			escape = 1
+			continue
+		case escape == 1 && (key == '[' || key == 'O'):
+			escape = 2
+			continue
+		case escape == 2:
+			// Parameter bytes such as "1;5" come before the final byte
+			if key >= 0x40 && key <= 0x7e {
+				escape = 0
+			}
+			continue
+		}
+		escape = 0
+		keys <- key
+	}
+}
+
+// handleKey applies a key press to the timer and returns false when the user quits
+func (tui *TUI) handleKey(key byte) bool {
+	confirming := tui.confirmShortRest
+	tui.confirmShortRest = false
+	tui.notice = ""
+	switch key {
+	case tuiKeyStartStop, 'S':
+		if rest, minimum, short := tui.timer.CheckRestBeforeStart(time.Now()); short && !confirming {
+			// Ask for a second key press instead of starting right away
+			tui.notice = fmt.Sprintf("Only %s of rest since your last shift (minimum %s). Press s again to start anyway.",
+				formatDuration(rest), formatDuration(minimum))
+			tui.confirmShortRest = true
+			return true
+		}
+		tui.timer.ToggleRunning()
+	case tuiKeyBreak, 'B':
+		tui.timer.ToggleBreak()
+	case tuiKeyCancel, 'C':
+		tui.timer.Reset()
+	case tuiKeyQuit, 'Q', keyCtrlC:
+		return false
+	default:
+		return true
+	}
+	tui.storage.SaveTimer(tui.timer)
+	return true
+}
+
+// render returns the screen content for the given terminal width
+func (tui *TUI) render(width int) string {
+	columnWidth := width / 2
+	if columnWidth < tuiMinColumnWidth {
+		columnWidth = tuiMinColumnWidth
+	}
+
+	today := [][2]string{
+		{formatTodaySession, formatDuration(tui.timer.GetTodaySessionTime())},
+		{formatBreakTime, formatDuration(tui.timer.GetCurrentBreakTime())},
+		{formatDailyTotal, formatDuration(tui.timer.GetDailyTime())},
+		{formatFirstStart, tui.timer.GetDayFirstStartTime()},
+		{formatLeaveAt, tui.timer.GetLeaveAtTime()},
+	}
+	yesterday := [][2]string{
+		{formatDailyTotal, formatDuration(tui.timer.YesterdayTotal)},
+		{formatFirstStart, tui.timer.GetYesterdayFirstStartTime()},
+		{formatLastEnd, tui.timer.GetYesterdayLastEndTime()},
+		{formatRest, tui.timer.GetRestTime()},
+	}
+
+	var b strings.Builder
+	status := tuiTextNotRunning
+	if tui.timer.IsOnBreak {
+		status = tuiTextOnBreak
+	} else if tui.timer.IsRunning {
+		status = tuiTextRunning
+	}
+	fmt.Fprintf(&b, "%s%s%s - %s\n\n", ansiBold, windowTitle, ansiReset, status)
+
+	fmt.Fprintf(&b, "%-*s%s%s%s\n", columnWidth, "", ansiBold, formatYesterdayStats, ansiReset)
+	for i, row := range today {
+		left := fmt.Sprintf("%s%s", row[0], row[1])
+		right := ""
+		if i < len(yesterday) {
+			right = fmt.Sprintf("%s%s", yesterday[i][0], yesterday[i][1])
+		}
+		fmt.Fprintf(&b, "%-*s%s\n", columnWidth, left, right)
+	}
+
+	fmt.Fprintf(&b, "\n%s%s\n", formatWeeklyTotal, formatDuration(tui.timer.GetWeeklyTime()))
+	fmt.Fprintf(&b, "%s%s\n", formatFlextime, formatSignedDuration(tui.timer.GetFlextimeBalance()))
+	fmt.Fprintf(&b, "%s%s\n\n", formatMonthlyTotal, formatDuration(tui.timer.GetMonthlyTime()))
+	for _, warning := range tui.timer.GetComplianceWarnings(time.Now()) {
+		fmt.Fprintf(&b, "%s! %s%s\n", ansiBold, warning.Message, ansiReset)
+	}
+	if tui.notice != "" {
+		fmt.Fprintf(&b, "%s%s%s\n", ansiBold, tui.notice, ansiReset)
+	}
+	fmt.Fprintln(&b, tuiTextHelp)
+	return b.String()
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestTUIKeysAndRender(t *testing.T) {
+	storage := newTestStorage(t)
+	timer := NewTimer()
+	timer.SetStorage(storage)
+	tui := NewTUI(timer, storage)
+
+	if !tui.handleKey('s') || !timer.IsRunning {
+		t.Fatal("'s' should start a session")
+	}
+	if !tui.handleKey('b') || !timer.IsOnBreak {
+		t.Fatal("'b' should start a break")
+	}
+	if screen := tui.render(80); !strings.Contains(screen, tuiTextOnBreak) || !strings.Contains(screen, formatYesterdayStats) {
+		t.Errorf("Screen should show break status and yesterday's stats, got:\n%s", screen)
+	}
+	if !tui.handleKey('c') || timer.IsRunning || timer.IsOnBreak {
+		t.Fatal("'c' should cancel the session")
+	}
+	if tui.handleKey('q') {
+		t.Error("'q' should quit")
+	}
+}
+
+func TestTUIIgnoresEscapeSequences(t *testing.T) {
+	storage := newTestStorage(t)
+	timer := NewTimer()
+	timer.SetStorage(storage)
+	tui := NewTUI(timer, storage)
+	if !tui.handleKey('s') || !timer.IsRunning {
+		t.Fatal("'s' should start a session")
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	tui.in = r
+	keys := make(chan byte)
+	go tui.readKeys(keys)
+	// Right, down (normal and application mode) and Ctrl+Right, then q
+	if _, err := w.WriteString("\x1b[C\x1b[B\x1bOC\x1b[1;5Cq"); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+
+	var received []byte
+	for key := range keys {
+		received = append(received, key)
+		tui.handleKey(key)
+	}
+	if string(received) != "q" {
+		t.Errorf("Expected only q to arrive, got %q", received)
+	}
+	if !timer.IsRunning || timer.IsOnBreak {
+		t.Error("Arrow keys should neither cancel the session nor start a break")
+	}
+}
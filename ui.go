@@ -7,6 +7,7 @@ import (
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/widget"
 )
@@ -14,7 +15,7 @@ import (
 const (
 	windowTitle  = "Time Tracker"
 	windowWidth  = 350
-	windowHeight = 300
+	windowHeight = 460
 
 	// Button text
 	textStart      = "▶️ Start Working Session"
@@ -22,17 +23,41 @@ const (
 	textStartBreak = "☕ Start Break"
 	textStopBreak  = "☕ Stop Break"
 	textCancel     = "❌ Cancel Working Session"
+	textWarning    = "⚠️ "
 
 	// Label formats
 	formatTodaySession   = "Today's Session: "
 	formatBreakTime      = "Current Break Time: "
 	formatWeeklyTotal    = "This Week's Total: "
+	formatMonthlyTotal   = "This Month's Total: "
+	formatFlextime       = "Flextime Balance: "
 	formatDailyTotal     = "Today's Total: "
 	formatFirstStart     = "Started at: "
+	formatLeaveAt        = "Leave at: "
+	formatLastEnd        = "Ended at: "
+	formatRest           = "Rest: "
 	formatYesterdayStats = "Yesterday's Stats"
+
+	// Dialog titles
+	titleShortRest = "Short Rest Period"
+
+	// Menu items
+	menuExportICS  = "Export Calendar (.ics)..."
+	menuExportHTML = "Export HTML Report..."
+	menuExportPDF  = "Export PDF Timesheet..."
+	menuInvoice    = "Create Invoice..."
+	menuHistory    = "History..."
+	menuMonthly    = "Monthly History..."
+	menuHeatmap    = "Heatmap..."
+	menuWeekly     = "Weekly Trend..."
+	menuStats      = "Statistics..."
+	menuAbsences   = "Absences..."
+	menuLedger     = "Balance Ledger..."
+	menuCompliance = "Compliance Report..."
 )
 
 type UI struct {
+	app                 fyne.App
 	window              fyne.Window
 	timer               *Timer
 	storage             *Storage
@@ -46,11 +71,22 @@ type UI struct {
 	todayTimeDesc       *widget.Label
 	breakDesc           *widget.Label
 	weeklyDesc          *widget.Label
+	monthlyLabel        *widget.Label
+	monthlyDesc         *widget.Label
+	flextimeLabel       *widget.Label
+	flextimeDesc        *widget.Label
 	dailyDesc           *widget.Label
 	firstStartDesc      *widget.Label
+	leaveAtLabel        *widget.Label
+	leaveAtDesc         *widget.Label
 	yesterdayTitle      *widget.Label
 	yesterdayDailyDesc  *widget.Label
 	yesterdayStartDesc  *widget.Label
+	yesterdayEndLabel   *widget.Label
+	yesterdayEndDesc    *widget.Label
+	restLabel           *widget.Label
+	restDesc            *widget.Label
+	warningLabel        *widget.Label
 	startButton         *widget.Button
 	breakButton         *widget.Button
 	cancelButton        *widget.Button
@@ -60,6 +96,7 @@ type UI struct {
 
 func NewUI(app fyne.App, timer *Timer, storage *Storage) *UI {
 	ui := &UI{
+		app:      app,
 		window:   app.NewWindow(windowTitle),
 		timer:    timer,
 		storage:  storage,
@@ -73,6 +110,7 @@ func NewUI(app fyne.App, timer *Timer, storage *Storage) *UI {
 
 	ui.createWidgets()
 	ui.layoutWidgets()
+	ui.createMenu()
 	ui.startUpdateTicker()
 
 	return ui
@@ -86,10 +124,16 @@ func (ui *UI) createWidgets() {
 	ui.breakLabel = widget.NewLabelWithStyle("0:00:00", fyne.TextAlignLeading, fyne.TextStyle{})
 	ui.weeklyDesc = widget.NewLabelWithStyle(formatWeeklyTotal, fyne.TextAlignTrailing, fyne.TextStyle{})
 	ui.weeklyLabel = widget.NewLabelWithStyle("0:00:00", fyne.TextAlignLeading, fyne.TextStyle{})
+	ui.monthlyDesc = widget.NewLabelWithStyle(formatMonthlyTotal, fyne.TextAlignTrailing, fyne.TextStyle{})
+	ui.monthlyLabel = widget.NewLabelWithStyle("0:00:00", fyne.TextAlignLeading, fyne.TextStyle{})
+	ui.flextimeDesc = widget.NewLabelWithStyle(formatFlextime, fyne.TextAlignTrailing, fyne.TextStyle{})
+	ui.flextimeLabel = widget.NewLabelWithStyle("+0:00:00", fyne.TextAlignLeading, fyne.TextStyle{})
 	ui.dailyDesc = widget.NewLabelWithStyle(formatDailyTotal, fyne.TextAlignTrailing, fyne.TextStyle{})
 	ui.dailyLabel = widget.NewLabelWithStyle("0:00:00", fyne.TextAlignLeading, fyne.TextStyle{})
 	ui.firstStartDesc = widget.NewLabelWithStyle(formatFirstStart, fyne.TextAlignTrailing, fyne.TextStyle{})
 	ui.firstStartLabel = widget.NewLabelWithStyle("Not started today", fyne.TextAlignLeading, fyne.TextStyle{})
+	ui.leaveAtDesc = widget.NewLabelWithStyle(formatLeaveAt, fyne.TextAlignTrailing, fyne.TextStyle{})
+	ui.leaveAtLabel = widget.NewLabelWithStyle("--:--", fyne.TextAlignLeading, fyne.TextStyle{})
 
 	// Create yesterday's stats labels with proper alignment
 	ui.yesterdayTitle = widget.NewLabelWithStyle(formatYesterdayStats, fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
@@ -97,6 +141,16 @@ func (ui *UI) createWidgets() {
 	ui.yesterdayDailyLabel = widget.NewLabelWithStyle("0:00:00", fyne.TextAlignLeading, fyne.TextStyle{})
 	ui.yesterdayStartDesc = widget.NewLabelWithStyle(formatFirstStart, fyne.TextAlignTrailing, fyne.TextStyle{})
 	ui.yesterdayStartLabel = widget.NewLabelWithStyle("No data", fyne.TextAlignLeading, fyne.TextStyle{})
+	ui.yesterdayEndDesc = widget.NewLabelWithStyle(formatLastEnd, fyne.TextAlignTrailing, fyne.TextStyle{})
+	ui.yesterdayEndLabel = widget.NewLabelWithStyle("No data", fyne.TextAlignLeading, fyne.TextStyle{})
+	ui.restDesc = widget.NewLabelWithStyle(formatRest, fyne.TextAlignTrailing, fyne.TextStyle{})
+	ui.restLabel = widget.NewLabelWithStyle("No data", fyne.TextAlignLeading, fyne.TextStyle{})
+
+	// Create compliance warning label, hidden while there are no warnings
+	ui.warningLabel = widget.NewLabelWithStyle("", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	ui.warningLabel.Importance = widget.WarningImportance
+	ui.warningLabel.Truncation = fyne.TextTruncateEllipsis
+	ui.warningLabel.Hide()
 
 	// Create buttons
 	ui.startButton = widget.NewButton(textStart, ui.handleStartStop)
@@ -117,6 +171,8 @@ func (ui *UI) layoutWidgets() {
 	todayGrid.Add(ui.dailyLabel)
 	todayGrid.Add(ui.firstStartDesc)
 	todayGrid.Add(ui.firstStartLabel)
+	todayGrid.Add(ui.leaveAtDesc)
+	todayGrid.Add(ui.leaveAtLabel)
 
 	// Create grid for yesterday's stats (2 columns)
 	yesterdayGrid := container.NewGridWithColumns(2)
@@ -124,6 +180,10 @@ func (ui *UI) layoutWidgets() {
 	yesterdayGrid.Add(ui.yesterdayDailyLabel)
 	yesterdayGrid.Add(ui.yesterdayStartDesc)
 	yesterdayGrid.Add(ui.yesterdayStartLabel)
+	yesterdayGrid.Add(ui.yesterdayEndDesc)
+	yesterdayGrid.Add(ui.yesterdayEndLabel)
+	yesterdayGrid.Add(ui.restDesc)
+	yesterdayGrid.Add(ui.restLabel)
 
 	// Create grid for both columns (2 columns)
 	mainGrid := container.NewGridWithColumns(2)
@@ -133,10 +193,14 @@ func (ui *UI) layoutWidgets() {
 		yesterdayGrid,
 	))
 
-	// Create grid for weekly total (2 columns, spans full width)
+	// Create grid for weekly, flextime and monthly totals (2 columns, spans full width)
 	weeklyGrid := container.NewGridWithColumns(2)
 	weeklyGrid.Add(ui.weeklyDesc)
 	weeklyGrid.Add(ui.weeklyLabel)
+	weeklyGrid.Add(ui.flextimeDesc)
+	weeklyGrid.Add(ui.flextimeLabel)
+	weeklyGrid.Add(ui.monthlyDesc)
+	weeklyGrid.Add(ui.monthlyLabel)
 
 	// Create button container with vertical layout
 	buttons := container.NewVBox(
@@ -149,6 +213,7 @@ func (ui *UI) layoutWidgets() {
 	content := container.NewVBox(
 		mainGrid,
 		weeklyGrid,
+		ui.warningLabel,
 		layout.NewSpacer(),
 		container.NewHBox(
 			layout.NewSpacer(),
@@ -160,7 +225,27 @@ func (ui *UI) layoutWidgets() {
 	ui.window.SetContent(content)
 }
 
-func (ui *UI) formatDuration(d time.Duration) string {
+func (ui *UI) createMenu() {
+	fileMenu := fyne.NewMenu("File",
+		fyne.NewMenuItem(menuExportICS, ui.handleExportICS),
+		fyne.NewMenuItem(menuExportHTML, ui.handleExportHTML),
+		fyne.NewMenuItem(menuExportPDF, ui.handleExportTimesheet),
+		fyne.NewMenuItem(menuInvoice, ui.handleCreateInvoice),
+	)
+	viewMenu := fyne.NewMenu("View",
+		fyne.NewMenuItem(menuHistory, ui.handleShowHistory),
+		fyne.NewMenuItem(menuMonthly, ui.handleShowMonthly),
+		fyne.NewMenuItem(menuHeatmap, ui.handleShowHeatmap),
+		fyne.NewMenuItem(menuWeekly, ui.handleShowWeekly),
+		fyne.NewMenuItem(menuStats, ui.handleShowStats),
+		fyne.NewMenuItem(menuAbsences, ui.handleShowAbsences),
+		fyne.NewMenuItem(menuLedger, ui.handleShowLedger),
+		fyne.NewMenuItem(menuCompliance, ui.handleShowCompliance),
+	)
+	ui.window.SetMainMenu(fyne.NewMainMenu(fileMenu, viewMenu))
+}
+
+func formatDuration(d time.Duration) string {
 	d = d.Round(time.Second)
 	h := d / time.Hour
 	d -= h * time.Hour
@@ -173,13 +258,26 @@ func (ui *UI) formatDuration(d time.Duration) string {
 func (ui *UI) updateLabels() {
 	fyne.Do(
 		func() {
-			ui.todayTimeLabel.SetText(ui.formatDuration(ui.timer.GetTodaySessionTime()))
-			ui.breakLabel.SetText(ui.formatDuration(ui.timer.GetCurrentBreakTime()))
-			ui.weeklyLabel.SetText(ui.formatDuration(ui.timer.GetWeeklyTime()))
-			ui.dailyLabel.SetText(ui.formatDuration(ui.timer.GetDailyTime()))
+			ui.todayTimeLabel.SetText(formatDuration(ui.timer.GetTodaySessionTime()))
+			ui.breakLabel.SetText(formatDuration(ui.timer.GetCurrentBreakTime()))
+			ui.weeklyLabel.SetText(formatDuration(ui.timer.GetWeeklyTime()))
+			ui.monthlyLabel.SetText(formatDuration(ui.timer.GetMonthlyTime()))
+			ui.flextimeLabel.SetText(formatSignedDuration(ui.timer.GetFlextimeBalance()))
+
+			// Show the first compliance warning, View > Compliance Report lists all of them
+			if warnings := ui.timer.GetComplianceWarnings(time.Now()); len(warnings) > 0 {
+				ui.warningLabel.SetText(textWarning + warnings[0].Message)
+				ui.warningLabel.Show()
+			} else {
+				ui.warningLabel.Hide()
+			}
+			ui.dailyLabel.SetText(formatDuration(ui.timer.GetDailyTime()))
 			ui.firstStartLabel.SetText(ui.timer.GetDayFirstStartTime())
-			ui.yesterdayDailyLabel.SetText(ui.formatDuration(ui.timer.YesterdayTotal))
+			ui.leaveAtLabel.SetText(ui.timer.GetLeaveAtTime())
+			ui.yesterdayDailyLabel.SetText(formatDuration(ui.timer.YesterdayTotal))
 			ui.yesterdayStartLabel.SetText(ui.timer.GetYesterdayFirstStartTime())
+			ui.yesterdayEndLabel.SetText(ui.timer.GetYesterdayLastEndTime())
+			ui.restLabel.SetText(ui.timer.GetRestTime())
 
 			// Update break button text with animated dots when on break
 			if ui.timer.IsOnBreak {
@@ -212,21 +310,27 @@ func (ui *UI) updateButtonStates() {
 }
 
 func (ui *UI) handleStartStop() {
-	if ui.timer.IsRunning {
-		ui.timer.Stop()
-	} else {
-		ui.timer.Start()
+	if rest, minimum, short := ui.timer.CheckRestBeforeStart(time.Now()); short {
+		message := fmt.Sprintf("Only %s of rest since your last shift, the minimum is %s.\nStart anyway?",
+			formatDuration(rest), formatDuration(minimum))
+		dialog.ShowConfirm(titleShortRest, message, func(start bool) {
+			if start {
+				ui.toggleRunning()
+			}
+		}, ui.window)
+		return
 	}
+	ui.toggleRunning()
+}
+
+func (ui *UI) toggleRunning() {
+	ui.timer.ToggleRunning()
 	ui.updateButtonStates()
 	ui.storage.SaveTimer(ui.timer)
 }
 
 func (ui *UI) handleBreak() {
-	if ui.timer.IsOnBreak || !ui.timer.IsRunning {
-		ui.timer.StopBreak()
-	} else {
-		ui.timer.StartBreak()
-	}
+	ui.timer.ToggleBreak()
 	ui.updateButtonStates()
 	ui.storage.SaveTimer(ui.timer)
 }
@@ -237,6 +341,259 @@ func (ui *UI) handleCancel() {
 	ui.storage.SaveTimer(ui.timer)
 }
 
+// askDateRange shows a form for an optional date range and calls onConfirm with the parsed bounds
+func (ui *UI) askDateRange(title string, onConfirm func(from, to time.Time)) {
+	fromEntry := widget.NewEntry()
+	fromEntry.SetPlaceHolder("YYYY-MM-DD (optional)")
+	toEntry := widget.NewEntry()
+	toEntry.SetPlaceHolder("YYYY-MM-DD (optional)")
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("From", fromEntry),
+		widget.NewFormItem("To", toEntry),
+	}
+	dialog.ShowForm(title, "OK", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		from, to, err := parseDateRange(fromEntry.Text, toEntry.Text)
+		if err != nil {
+			dialog.ShowError(err, ui.window)
+			return
+		}
+		onConfirm(from, to)
+	}, ui.window)
+}
+
+func (ui *UI) handleExportICS() {
+	ui.askDateRange(menuExportICS, func(from, to time.Time) {
+		sessions, err := ui.storage.LoadSessions(from, to)
+		if err != nil {
+			dialog.ShowError(err, ui.window)
+			return
+		}
+
+		save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, ui.window)
+				return
+			}
+			if writer == nil {
+				return // Cancelled
+			}
+			defer writer.Close()
+			if err := WriteICS(writer, sessions); err != nil {
+				dialog.ShowError(err, ui.window)
+			}
+		}, ui.window)
+		save.SetFileName("timetracker.ics")
+		save.Show()
+	})
+}
+
+func (ui *UI) handleExportHTML() {
+	ui.askDateRange(menuExportHTML, func(from, to time.Time) {
+		report, err := ui.storage.PeriodReport(from, to)
+		if err != nil {
+			dialog.ShowError(err, ui.window)
+			return
+		}
+
+		save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, ui.window)
+				return
+			}
+			if writer == nil {
+				return // Cancelled
+			}
+			defer writer.Close()
+			if err := WriteHTMLReport(writer, report); err != nil {
+				dialog.ShowError(err, ui.window)
+			}
+		}, ui.window)
+		save.SetFileName("timetracker-report.html")
+		save.Show()
+	})
+}
+
+func (ui *UI) handleExportTimesheet() {
+	today, err := ui.storage.Today()
+	if err != nil {
+		dialog.ShowError(err, ui.window)
+		return
+	}
+	monthEntry := widget.NewEntry()
+	monthEntry.SetText(today.Format("2006-01"))
+	items := []*widget.FormItem{widget.NewFormItem("Month", monthEntry)}
+
+	dialog.ShowForm(menuExportPDF, "OK", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		month, err := time.ParseInLocation("2006-01", monthEntry.Text, time.Local)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("invalid month %q, use YYYY-MM", monthEntry.Text), ui.window)
+			return
+		}
+		sheet, err := ui.storage.Timesheet(month.Year(), month.Month())
+		if err != nil {
+			dialog.ShowError(err, ui.window)
+			return
+		}
+
+		save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, ui.window)
+				return
+			}
+			if writer == nil {
+				return // Cancelled
+			}
+			defer writer.Close()
+			if err := WriteTimesheetPDF(writer, sheet); err != nil {
+				dialog.ShowError(err, ui.window)
+			}
+		}, ui.window)
+		save.SetFileName("timesheet-" + monthEntry.Text + ".pdf")
+		save.Show()
+	}, ui.window)
+}
+
+// handleCreateInvoice bills the unbilled sessions of a client; they are marked as billed
+// when the invoice is saved
+func (ui *UI) handleCreateInvoice() {
+	config, err := ui.storage.LoadConfig()
+	if err != nil {
+		dialog.ShowError(err, ui.window)
+		return
+	}
+	clients := config.Billing.clientNames()
+	if len(clients) == 0 {
+		dialog.ShowInformation(menuInvoice, "Add clients and rates to the \"billing\" setting in config.json first.", ui.window)
+		return
+	}
+	today, err := ui.storage.Today()
+	if err != nil {
+		dialog.ShowError(err, ui.window)
+		return
+	}
+	clientSelect := widget.NewSelect(clients, nil)
+	clientSelect.SetSelectedIndex(0)
+	fromEntry := widget.NewEntry()
+	fromEntry.SetText(today.Format("2006-01") + "-01")
+	toEntry := widget.NewEntry()
+	toEntry.SetText(today.Format("2006-01-02"))
+	items := []*widget.FormItem{
+		widget.NewFormItem("Client", clientSelect),
+		widget.NewFormItem("From", fromEntry),
+		widget.NewFormItem("To", toEntry),
+	}
+
+	dialog.ShowForm(menuInvoice, "OK", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		from, to, err := parseDateRange(fromEntry.Text, toEntry.Text)
+		if err != nil {
+			dialog.ShowError(err, ui.window)
+			return
+		}
+		invoice, err := ui.storage.PrepareInvoice(clientSelect.Selected, from, to)
+		if err != nil {
+			dialog.ShowError(err, ui.window)
+			return
+		}
+
+		save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, ui.window)
+				return
+			}
+			if writer == nil {
+				return // Cancelled
+			}
+			// Record first, so no invoice is handed out whose sessions are still billable
+			if err := ui.storage.RecordInvoice(invoice); err != nil {
+				writer.Close()
+				dialog.ShowError(err, ui.window)
+				return
+			}
+			err = writeInvoice(writer, writer.URI().Name(), invoice)
+			// Closing flushes the file, so its error counts like a failed write
+			if closeErr := writer.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				if discardErr := ui.storage.discardInvoice(invoice.Number); discardErr != nil {
+					err = fmt.Errorf("%w; invoice %s stays recorded: %v", err, invoice.Number, discardErr)
+				}
+				dialog.ShowError(err, ui.window)
+				return
+			}
+			billed, recorded := invoice.BilledHours()
+			dialog.ShowInformation(menuInvoice, fmt.Sprintf("Invoice %s over %s saved (%s h billed, %s h recorded).\nIts sessions are marked as billed.",
+				invoice.Number, formatMoney(invoice.Total, invoice.Currency), formatHours(billed), formatHours(recorded)), ui.window)
+		}, ui.window)
+		save.SetFileName("invoice-" + invoice.Number + ".html")
+		save.Show()
+	}, ui.window)
+}
+
+func (ui *UI) handleShowHistory() {
+	NewHistoryWindow(ui.app, ui.storage).Show()
+}
+
+func (ui *UI) handleShowMonthly() {
+	NewMonthlyWindow(ui.app, ui.storage).Show()
+}
+
+func (ui *UI) handleShowHeatmap() {
+	NewHeatmapWindow(ui.app, ui.storage).Show()
+}
+
+func (ui *UI) handleShowWeekly() {
+	NewWeeklyWindow(ui.app, ui.storage).Show()
+}
+
+func (ui *UI) handleShowStats() {
+	NewStatsWindow(ui.app, ui.storage).Show()
+}
+
+func (ui *UI) handleShowAbsences() {
+	NewAbsenceWindow(ui.app, ui.storage).Show()
+}
+
+func (ui *UI) handleShowLedger() {
+	NewLedgerWindow(ui.app, ui.storage).Show()
+}
+
+func (ui *UI) handleShowCompliance() {
+	ui.askDateRange(menuCompliance, func(from, to time.Time) {
+		violations, err := ui.storage.Compliance(from, to)
+		if err != nil {
+			dialog.ShowError(err, ui.window)
+			return
+		}
+
+		lines := make([]string, len(violations))
+		for i, violation := range violations {
+			lines[i] = violation.String()
+		}
+		if len(lines) == 0 {
+			lines = append(lines, "No violations found")
+		}
+		list := widget.NewList(
+			func() int { return len(lines) },
+			func() fyne.CanvasObject { return widget.NewLabel("") },
+			func(id widget.ListItemID, item fyne.CanvasObject) { item.(*widget.Label).SetText(lines[id]) },
+		)
+		report := dialog.NewCustom(menuCompliance, "Close", list, ui.window)
+		report.Resize(fyne.NewSize(windowWidth, windowHeight))
+		report.Show()
+	})
+}
+
 func (ui *UI) startUpdateTicker() {
 	ui.updateTicker = time.NewTicker(250 * time.Millisecond)
 	go func() {
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	weeklyWindowTitle  = "Weekly Trend"
+	weeklyWindowWidth  = 700
+	weeklyWindowHeight = 400
+	weeklyDefaultWeeks = 12
+	weeklyChartLabelH  = 18
+)
+
+var (
+	weeklyWeekOptions = []string{"8", "12", "26", "52"}
+	weeklyWorkColor   = color.NRGBA{0x30, 0xa1, 0x4e, 0xff}
+	weeklyBreakColor  = color.NRGBA{0xf0, 0xb4, 0x29, 0xff}
+	weeklyTargetColor = color.NRGBA{0xd7, 0x3a, 0x49, 0xff}
+)
+
+// NewWeeklyWindow creates a window with a bar chart of the last weeks
+func NewWeeklyWindow(app fyne.App, storage *Storage) fyne.Window {
+	window := app.NewWindow(weeklyWindowTitle)
+	chart := newWeeklyChart()
+	status := widget.NewLabel("")
+
+	load := func(n int) {
+		today, err := storage.Today()
+		if err != nil {
+			status.SetText("Error: " + err.Error())
+			return
+		}
+		weeks, err := storage.WeeklySummaries(n, today)
+		if err != nil {
+			status.SetText("Error: " + err.Error())
+			return
+		}
+		var worked time.Duration
+		for _, week := range weeks {
+			worked += week.Worked
+		}
+		status.SetText(fmt.Sprintf("Average %s per week", formatDuration(worked/time.Duration(n))))
+		chart.SetWeeks(weeks)
+	}
+
+	weeksSelect := widget.NewSelect(weeklyWeekOptions, func(value string) {
+		if n, err := strconv.Atoi(value); err == nil {
+			load(n)
+		}
+	})
+	legend := container.NewHBox(
+		weeklyLegendItem(weeklyWorkColor, "Worked"),
+		weeklyLegendItem(weeklyBreakColor, "Breaks"),
+		weeklyLegendItem(weeklyTargetColor, "Target"),
+	)
+
+	window.SetContent(container.NewBorder(
+		container.NewHBox(widget.NewLabel("Weeks:"), weeksSelect, legend),
+		status,
+		nil, nil,
+		chart,
+	))
+	weeksSelect.SetSelected(strconv.Itoa(weeklyDefaultWeeks))
+	window.Resize(fyne.NewSize(weeklyWindowWidth, weeklyWindowHeight))
+	return window
+}
+
+func weeklyLegendItem(fill color.Color, text string) fyne.CanvasObject {
+	swatch := canvas.NewRectangle(fill)
+	swatch.SetMinSize(fyne.NewSize(12, 12))
+	return container.NewHBox(container.NewCenter(swatch), widget.NewLabel(text))
+}
+
+// weeklyChart draws worked time with breaks stacked on top and the target as a line per week
+type weeklyChart struct {
+	widget.BaseWidget
+	weeks []WeeklySummary
+}
+
+func newWeeklyChart() *weeklyChart {
+	chart := &weeklyChart{}
+	chart.ExtendBaseWidget(chart)
+	return chart
+}
+
+// SetWeeks replaces the displayed weeks
+func (c *weeklyChart) SetWeeks(weeks []WeeklySummary) {
+	c.weeks = weeks
+	c.Refresh()
+}
+
+func (c *weeklyChart) CreateRenderer() fyne.WidgetRenderer {
+	r := &weeklyChartRenderer{chart: c}
+	r.rebuild()
+	return r
+}
+
+type weeklyChartRenderer struct {
+	chart   *weeklyChart
+	work    []*canvas.Rectangle
+	breaks  []*canvas.Rectangle
+	targets []*canvas.Line
+	labels  []*canvas.Text
+	objects []fyne.CanvasObject
+}
+
+// rebuild creates one set of canvas objects per week
+func (r *weeklyChartRenderer) rebuild() {
+	r.work, r.breaks, r.targets, r.labels, r.objects = nil, nil, nil, nil, nil
+	for _, week := range r.chart.weeks {
+		work := canvas.NewRectangle(weeklyWorkColor)
+		breaks := canvas.NewRectangle(weeklyBreakColor)
+		target := canvas.NewLine(weeklyTargetColor)
+		target.StrokeWidth = 2
+		label := canvas.NewText(fmt.Sprintf("W%02d", week.Week), theme.Color(theme.ColorNameForeground))
+		label.TextSize = theme.CaptionTextSize()
+		label.Alignment = fyne.TextAlignCenter
+
+		r.work = append(r.work, work)
+		r.breaks = append(r.breaks, breaks)
+		r.targets = append(r.targets, target)
+		r.labels = append(r.labels, label)
+		r.objects = append(r.objects, work, breaks, target, label)
+	}
+}
+
+func (r *weeklyChartRenderer) Layout(size fyne.Size) {
+	weeks := r.chart.weeks
+	if len(weeks) == 0 {
+		return
+	}
+
+	// Scale to the highest bar or target line with some headroom
+	var max time.Duration
+	for _, week := range weeks {
+		if total := week.Worked + week.Break; total > max {
+			max = total
+		}
+		if week.Target > max {
+			max = week.Target
+		}
+	}
+	if max == 0 {
+		max = time.Hour
+	}
+	chartHeight := size.Height - weeklyChartLabelH
+	scale := chartHeight / float32(max.Hours()*1.1)
+
+	slot := size.Width / float32(len(weeks))
+	barWidth := slot * 0.6
+	for i, week := range weeks {
+		x := slot*float32(i) + (slot-barWidth)/2
+		workHeight := float32(week.Worked.Hours()) * scale
+		breakHeight := float32(week.Break.Hours()) * scale
+
+		r.work[i].Move(fyne.NewPos(x, chartHeight-workHeight))
+		r.work[i].Resize(fyne.NewSize(barWidth, workHeight))
+		r.breaks[i].Move(fyne.NewPos(x, chartHeight-workHeight-breakHeight))
+		r.breaks[i].Resize(fyne.NewSize(barWidth, breakHeight))
+
+		targetY := chartHeight - float32(week.Target.Hours())*scale
+		r.targets[i].Position1 = fyne.NewPos(slot*float32(i), targetY)
+		r.targets[i].Position2 = fyne.NewPos(slot*float32(i+1), targetY)
+		r.targets[i].Hidden = week.Target == 0
+
+		r.labels[i].Move(fyne.NewPos(slot*float32(i), chartHeight))
+		r.labels[i].Resize(fyne.NewSize(slot, weeklyChartLabelH))
+	}
+}
+
+func (r *weeklyChartRenderer) MinSize() fyne.Size {
+	return fyne.NewSize(float32(len(r.chart.weeks))*8, 100)
+}
+
+func (r *weeklyChartRenderer) Refresh() {
+	r.rebuild()
+	r.Layout(r.chart.Size())
+	canvas.Refresh(r.chart)
+}
+
+func (r *weeklyChartRenderer) Objects() []fyne.CanvasObject {
+	return r.objects
+}
+
+func (r *weeklyChartRenderer) Destroy() {}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Values of the "week_start" config setting besides weekday names
+const (
+	weekStartISO = "iso" // Monday, weeks numbered by ISO 8601
+	weekStartUS  = "us"  // Sunday, week 1 contains January 1
+)
+
+// FirstWeekday returns the configured first day of the week, Monday by default
+func (c *Config) FirstWeekday() time.Weekday {
+	switch strings.ToLower(c.WeekStart) {
+	case "", weekStartISO:
+		return time.Monday
+	case weekStartUS:
+		return time.Sunday
+	}
+	day, err := parseWeekday(c.WeekStart)
+	if err != nil {
+		return time.Monday
+	}
+	return day
+}
+
+// WeekOf returns the year and number of the week containing date. Weeks starting on
+// Monday follow ISO 8601; other weeks are numbered so that week 1 contains January 1,
+// with the year taken from the week's last day.
+func (c *Config) WeekOf(date time.Time) (year, week int) {
+	first := c.FirstWeekday()
+	if first == time.Monday {
+		return date.ISOWeek()
+	}
+	last := startOfWeek(date, first).AddDate(0, 0, 6)
+	return last.Year(), (last.YearDay()-1)/7 + 1
+}
+
+// startOfWeek returns midnight of the first day of the week containing date
+func startOfWeek(date time.Time, first time.Weekday) time.Time {
+	offset := (int(date.Weekday()) - int(first) + 7) % 7
+	return time.Date(date.Year(), date.Month(), date.Day()-offset, 0, 0, 0, 0, date.Location())
+}
+
+// validateWeekStart checks the "week_start" setting
+func validateWeekStart(value string) error {
+	switch strings.ToLower(value) {
+	case "", weekStartISO, weekStartUS:
+		return nil
+	}
+	if _, err := parseWeekday(value); err != nil {
+		return fmt.Errorf("week_start must be %q, %q or a weekday such as \"sat\"", weekStartISO, weekStartUS)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWeekOf(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 12, 0, 0, 0, time.Local)
+	}
+	tests := []struct {
+		weekStart string
+		date      time.Time
+		year      int
+		week      int
+	}{
+		// 2026-12-31 and 2027-01-01 are both in ISO week 53 of 2026
+		{"", date(2026, 12, 31), 2026, 53},
+		{"iso", date(2027, 1, 1), 2026, 53},
+		{"iso", date(2027, 1, 4), 2027, 1},
+		// 2024-12-30 belongs to ISO week 1 of 2025
+		{"iso", date(2024, 12, 30), 2025, 1},
+		// US weeks: the week of January 1 is week 1, even if it starts in December
+		{"us", date(2026, 12, 31), 2027, 1},
+		{"us", date(2027, 1, 2), 2027, 1},
+		{"us", date(2027, 1, 3), 2027, 2},
+		{"sat", date(2027, 1, 1), 2027, 1},
+		{"sat", date(2027, 1, 2), 2027, 2},
+	}
+	for _, tt := range tests {
+		config := &Config{WeekStart: tt.weekStart}
+		if year, week := config.WeekOf(tt.date); year != tt.year || week != tt.week {
+			t.Errorf("%q %s: expected %d-W%02d, got %d-W%02d", tt.weekStart, tt.date.Format("2006-01-02"), tt.year, tt.week, year, week)
+		}
+	}
+
+	if got := startOfWeek(date(2027, 1, 1), time.Sunday).Format("2006-01-02"); got != "2026-12-27" {
+		t.Errorf("Expected the US week to start on 2026-12-27, got %s", got)
+	}
+	if err := (&Config{WeekStart: "someday"}).Validate(); err == nil {
+		t.Error("Invalid week_start should fail validation")
+	}
+}
+
+func TestWeeklyTotalAcrossNewYear(t *testing.T) {
+	storage := newTestStorage(t)
+	config := &Config{WeeklyTargetHours: 40, WeekStart: "us"}
+	if err := storage.SaveConfig(config); err != nil {
+		t.Fatal(err)
+	}
+	sessions := []Session{
+		{Date: "2026-12-27", Duration: 3600}, // Sunday, first day of the US week
+		{Date: "2027-01-01", Duration: 2 * 3600},
+		{Date: "2026-12-26", Duration: 4 * 3600}, // Saturday of the previous week
+	}
+	if err := storage.appendSessionsToCSV(sessions); err != nil {
+		t.Fatal(err)
+	}
+
+	weeks, err := storage.WeeklySummaries(2, time.Date(2027, 1, 2, 12, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatalf("WeeklySummaries failed: %v", err)
+	}
+	if weeks[1].Worked != 3*time.Hour || weeks[1].Year != 2027 || weeks[1].Week != 1 {
+		t.Errorf("Expected 3h in 2027-W01, got %+v", weeks[1])
+	}
+	if weeks[0].Worked != 4*time.Hour || weeks[0].Start.Format("2006-01-02") != "2026-12-20" {
+		t.Errorf("Expected 4h in the week starting 2026-12-20, got %+v", weeks[0])
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	// Embedded zone database, so recorded zones load on systems without one (Windows, wasm)
+	_ "time/tzdata"
+)
+
+// Values of the "report_time_zone" config setting besides zone names
+const (
+	reportZoneRecorded = "recorded" // the zone each session was recorded in, the default
+	reportZoneLocal    = "local"    // the zone of the computer showing the report
+)
+
+// zoneCache holds the locations loaded by loadZone, keyed by name
+var zoneCache sync.Map
+
+// loadZone returns the location of a recorded zone, either an IANA name such as
+// "Europe/Berlin" or a fixed UTC offset such as "+02:00". ok is false for unknown zones.
+func loadZone(name string) (*time.Location, bool) {
+	if name == "" {
+		return nil, false
+	}
+	if cached, ok := zoneCache.Load(name); ok {
+		return cached.(*time.Location), true
+	}
+
+	var loc *time.Location
+	if name[0] == '+' || name[0] == '-' {
+		offset, err := time.Parse("-07:00", name)
+		if err != nil {
+			return nil, false
+		}
+		_, seconds := offset.Zone()
+		loc = time.FixedZone(name, seconds)
+	} else {
+		var err error
+		if loc, err = time.LoadLocation(name); err != nil {
+			return nil, false
+		}
+	}
+	zoneCache.Store(name, loc)
+	return loc, true
+}
+
+// localZoneName returns the IANA name of the local time zone, or "" if it cannot be determined
+var localZoneName = sync.OnceValue(func() string {
+	if name := strings.TrimPrefix(os.Getenv("TZ"), ":"); name != "" {
+		if _, ok := loadZone(name); ok {
+			return name
+		}
+		return ""
+	}
+	// On Linux and macOS /etc/localtime links into the zone database
+	if target, err := os.Readlink("/etc/localtime"); err == nil {
+		if _, name, ok := strings.Cut(target, "zoneinfo/"); ok {
+			if _, ok := loadZone(name); ok {
+				return name
+			}
+		}
+	}
+	return ""
+})
+
+// recordedZone returns the zone stored with a session starting at t: the name of the
+// local zone if known, otherwise the UTC offset at t
+func recordedZone(t time.Time) string {
+	if name := localZoneName(); name != "" && t.Location() == time.Local {
+		return name
+	}
+	return t.Format("-07:00")
+}
+
+// In returns the session with its start, end and breaks shown in loc
+func (session Session) In(loc *time.Location) Session {
+	if !session.Start.IsZero() {
+		session.Start = session.Start.In(loc)
+	}
+	if !session.End.IsZero() {
+		session.End = session.End.In(loc)
+	}
+	if len(session.Breaks) > 0 {
+		breaks := make([]Break, len(session.Breaks))
+		for i, b := range session.Breaks {
+			breaks[i] = Break{Start: b.Start.In(loc), End: b.End.In(loc)}
+		}
+		session.Breaks = breaks
+	}
+	return session
+}
+
+// ReportLocation returns the zone in which reports show times, or nil to show
+// every session in the zone it was recorded in
+func (c *Config) ReportLocation() *time.Location {
+	switch strings.ToLower(c.ReportTimeZone) {
+	case "", reportZoneRecorded:
+		return nil
+	case reportZoneLocal:
+		return time.Local
+	}
+	loc, ok := loadZone(c.ReportTimeZone)
+	if !ok {
+		return nil
+	}
+	return loc
+}
+
+// validateReportZone checks the "report_time_zone" setting
+func validateReportZone(value string) error {
+	switch strings.ToLower(value) {
+	case "", reportZoneRecorded, reportZoneLocal:
+		return nil
+	}
+	if _, ok := loadZone(value); !ok {
+		return fmt.Errorf("report_time_zone must be %q, %q or a zone such as \"Europe/Berlin\"", reportZoneRecorded, reportZoneLocal)
+	}
+	return nil
+}
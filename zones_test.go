@@ -0,0 +1,151 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSessionZoneRoundTrip(t *testing.T) {
+	storage := newTestStorage(t)
+	newYork, _ := loadZone("America/New_York")
+	start := time.Date(2026, 7, 1, 9, 0, 0, 0, newYork)
+	session := Session{
+		Date:     "2026-07-01",
+		Duration: 3600,
+		Start:    start,
+		End:      start.Add(time.Hour),
+		Breaks:   []Break{{Start: start.Add(10 * time.Minute), End: start.Add(20 * time.Minute)}},
+		Zone:     "America/New_York",
+	}
+	legacy := []string{"2026-07-02", "3600", "0", "2026-07-02T09:00:00+05:30", "2026-07-02T10:00:00+05:30"}
+	if err := storage.appendSessionsToCSV([]Session{session}); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.OpenFile(storage.csvFile, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString(strings.Join(legacy, ",") + "\n")
+	file.Close()
+
+	data, err := os.ReadFile(storage.csvFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "2026-07-01T13:00:00Z") {
+		t.Errorf("Expected the start to be stored in UTC, got:\n%s", data)
+	}
+
+	sessions, err := storage.loadSessionsFromCSV()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("Expected 2 sessions, got %d", len(sessions))
+	}
+	got := sessions[0]
+	if !got.Start.Equal(start) || got.Start.Format("15:04") != "09:00" || got.Zone != "America/New_York" {
+		t.Errorf("Expected 09:00 in New York, got %s (%q)", got.Start.Format(time.RFC3339), got.Zone)
+	}
+	if got.Breaks[0].Start.Format("15:04") != "09:10" {
+		t.Errorf("Expected the break in the recorded zone, got %s", got.Breaks[0].Start.Format(time.RFC3339))
+	}
+	// Rows written before zones were recorded keep their offset
+	if clock := sessions[1].Start.Format("15:04 -07:00"); clock != "09:00 +05:30" {
+		t.Errorf("Expected the legacy row at 09:00 +05:30, got %s", clock)
+	}
+}
+
+func TestReportTimeZone(t *testing.T) {
+	storage := newTestStorage(t)
+	berlin, _ := loadZone("Europe/Berlin")
+	start := time.Date(2026, 7, 1, 23, 30, 0, 0, berlin)
+	session := Session{Date: "2026-07-01", Duration: 3600, Start: start, End: start.Add(time.Hour), Zone: "Europe/Berlin"}
+	if err := storage.appendSessionsToCSV([]Session{session}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		zone  string
+		clock string
+	}{
+		{"", "23:30"},
+		{"recorded", "23:30"},
+		{"Asia/Tokyo", "06:30"},
+	} {
+		if err := storage.SaveConfig(&Config{WeeklyTargetHours: 40, ReportTimeZone: tt.zone}); err != nil {
+			t.Fatal(err)
+		}
+		days, err := storage.DailySummaries(time.Date(2026, 7, 1, 0, 0, 0, 0, time.Local), time.Date(2026, 7, 1, 0, 0, 0, 0, time.Local))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The session stays on the day it was worked, only the clock time changes
+		if len(days[0].Sessions) != 1 || days[0].Worked != time.Hour {
+			t.Fatalf("%q: expected the session on 2026-07-01, got %+v", tt.zone, days[0])
+		}
+		if clock := days[0].FirstStart().Format("15:04"); clock != tt.clock {
+			t.Errorf("%q: expected the start at %s, got %s", tt.zone, tt.clock, clock)
+		}
+
+		// Queries compare against the same clock time
+		query, _ := ParseQuery("start = " + tt.clock)
+		matches := 0
+		if err := storage.QuerySessions(query, func(Session) bool { matches++; return true }); err != nil {
+			t.Fatal(err)
+		}
+		if matches != 1 {
+			t.Errorf("%q: expected the query to match the start at %s", tt.zone, tt.clock)
+		}
+	}
+
+	if err := (&Config{ReportTimeZone: "Mars/Olympus"}).Validate(); err == nil {
+		t.Error("Unknown report_time_zone should fail validation")
+	}
+}
+
+func TestLogicalDayAcrossDST(t *testing.T) {
+	berlin, _ := loadZone("Europe/Berlin")
+	config := &Config{DayStartsAt: "04:00"}
+	// On 2026-03-29 clocks jump from 02:00 to 03:00, on 2026-10-25 from 03:00 back to 02:00
+	tests := []struct {
+		time time.Time
+		date string
+	}{
+		{time.Date(2026, 3, 29, 3, 30, 0, 0, berlin), "2026-03-28"},
+		{time.Date(2026, 3, 29, 4, 30, 0, 0, berlin), "2026-03-29"},
+		{time.Date(2026, 10, 25, 3, 59, 0, 0, berlin), "2026-10-24"},
+		{time.Date(2026, 10, 25, 4, 0, 0, 0, berlin), "2026-10-25"},
+	}
+	for _, tt := range tests {
+		if got := config.LogicalDate(tt.time); got != tt.date {
+			t.Errorf("%s: expected %s, got %s", tt.time.Format(time.RFC3339), tt.date, got)
+		}
+	}
+
+	// A session across the spring-forward change lasts one hour less than the clock suggests
+	start := time.Date(2026, 3, 29, 1, 0, 0, 0, berlin)
+	end := time.Date(2026, 3, 29, 4, 0, 0, 0, berlin)
+	if session := sessionFromRange(start, end, 0, "", ""); session.Duration != 2*3600 {
+		t.Errorf("Expected 2h across the DST change, got %ds", session.Duration)
+	}
+}
+
+func TestLoadZone(t *testing.T) {
+	loc, ok := loadZone("+05:30")
+	if !ok {
+		t.Fatal("Expected a UTC offset to load")
+	}
+	if _, offset := time.Date(2026, 1, 1, 0, 0, 0, 0, loc).Zone(); offset != 5*3600+30*60 {
+		t.Errorf("Expected an offset of 5:30, got %ds", offset)
+	}
+	if _, ok := loadZone("Nowhere/Special"); ok {
+		t.Error("Unknown zones should not load")
+	}
+	fixed := time.FixedZone("", -3*3600)
+	if zone := recordedZone(time.Date(2026, 1, 1, 9, 0, 0, 0, fixed)); zone != "-03:00" {
+		t.Errorf("Expected the offset for a time outside the local zone, got %q", zone)
+	}
+}